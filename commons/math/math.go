@@ -0,0 +1,228 @@
+// Package math provides numeric aggregations of a Flux, like its sum or
+// average. The items can be of any of the built-in integer or floating point
+// types, a Flux emitting any other item fails with
+// cesium.NonNumericItemError.
+package math
+
+import (
+	gomath "math"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// Statistics summarizes the numeric items of a Flux. It is emitted by Summary.
+type Statistics struct {
+	Count  int64
+	Mean   float64
+	StdDev float64
+}
+
+// number is a numeric item, kept as int64 if it is an integer that fits.
+type number struct {
+	integer int64
+	float   float64
+	isFloat bool
+}
+
+func toNumber(t cesium.T) (number, bool) {
+	switch v := t.(type) {
+	case int:
+		return number{integer: int64(v)}, true
+	case int8:
+		return number{integer: int64(v)}, true
+	case int16:
+		return number{integer: int64(v)}, true
+	case int32:
+		return number{integer: int64(v)}, true
+	case int64:
+		return number{integer: v}, true
+	case uint:
+		return fromUint64(uint64(v)), true
+	case uint8:
+		return number{integer: int64(v)}, true
+	case uint16:
+		return number{integer: int64(v)}, true
+	case uint32:
+		return number{integer: int64(v)}, true
+	case uint64:
+		return fromUint64(v), true
+	case float32:
+		return number{float: float64(v), isFloat: true}, true
+	case float64:
+		return number{float: v, isFloat: true}, true
+	}
+
+	return number{}, false
+}
+
+func fromUint64(v uint64) number {
+	if v > gomath.MaxInt64 {
+		return number{float: float64(v), isFloat: true}
+	}
+
+	return number{integer: int64(v)}
+}
+
+func (n number) toFloat() float64 {
+	if n.isFloat {
+		return n.float
+	}
+
+	return float64(n.integer)
+}
+
+func (n number) add(other number) number {
+	if n.isFloat || other.isFloat {
+		return number{float: n.toFloat() + other.toFloat(), isFloat: true}
+	}
+
+	return number{integer: n.integer + other.integer}
+}
+
+func (n number) less(other number) bool {
+	if n.isFloat || other.isFloat {
+		return n.toFloat() < other.toFloat()
+	}
+
+	return n.integer < other.integer
+}
+
+func (n number) value() cesium.T {
+	if n.isFloat {
+		return n.float
+	}
+
+	return n.integer
+}
+
+// numbers fails the Flux with cesium.NonNumericItemError on the first item
+// that is not a number.
+func numbers(f cesium.Flux) cesium.Flux {
+	return f.Handle(func(t cesium.T, sink cesium.SynchronousSink) {
+		if _, ok := toNumber(t); !ok {
+			sink.Error(cesium.NonNumericItemError)
+			return
+		}
+
+		sink.Next(t)
+	})
+}
+
+// Sum emits the sum of the items, 0 if there are none. The sum is an int64 if
+// all the items are integers, and a float64 otherwise.
+func Sum(f cesium.Flux) cesium.Mono {
+	return numbers(f).
+		ReduceWith(
+			func() cesium.T {
+				return number{}
+			},
+			func(sum cesium.T, t cesium.T) cesium.T {
+				n, _ := toNumber(t)
+				return sum.(number).add(n)
+			},
+		).
+		Map(func(sum cesium.T) cesium.T {
+			return sum.(number).value()
+		})
+}
+
+// Average emits the arithmetic mean of the items as a float64. It completes
+// empty if there are no items.
+func Average(f cesium.Flux) cesium.Mono {
+	return Summary(f).
+		Filter(func(t cesium.T) bool {
+			return t.(Statistics).Count > 0
+		}).
+		Map(func(t cesium.T) cesium.T {
+			return t.(Statistics).Mean
+		})
+}
+
+// Min emits the least of the items. It completes empty if there are no items.
+func Min(f cesium.Flux) cesium.Mono {
+	return extreme(f, func(candidate number, current number) bool {
+		return candidate.less(current)
+	})
+}
+
+// Max emits the greatest of the items. It completes empty if there are no
+// items.
+func Max(f cesium.Flux) cesium.Mono {
+	return extreme(f, func(candidate number, current number) bool {
+		return current.less(candidate)
+	})
+}
+
+// extreme emits the item that replaces all the other ones.
+func extreme(f cesium.Flux, replaces func(candidate number, current number) bool) cesium.Mono {
+	type extremeItem struct {
+		item   cesium.T
+		number number
+		found  bool
+	}
+
+	return numbers(f).
+		ReduceWith(
+			func() cesium.T {
+				return extremeItem{}
+			},
+			func(current cesium.T, t cesium.T) cesium.T {
+				e := current.(extremeItem)
+				n, _ := toNumber(t)
+				if !e.found || replaces(n, e.number) {
+					return extremeItem{t, n, true}
+				}
+
+				return e
+			},
+		).
+		Filter(func(t cesium.T) bool {
+			return t.(extremeItem).found
+		}).
+		Map(func(t cesium.T) cesium.T {
+			return t.(extremeItem).item
+		})
+}
+
+// Summary emits the Statistics of the items, with the population standard
+// deviation. All of its fields are 0 if there are no items.
+func Summary(f cesium.Flux) cesium.Mono {
+	// The mean and the sum of squared differences from it are updated for
+	// each item (Welford's algorithm), to stay precise for long sequences.
+	type summary struct {
+		count int64
+		mean  float64
+		m2    float64
+	}
+
+	return numbers(f).
+		ReduceWith(
+			func() cesium.T {
+				return summary{}
+			},
+			func(current cesium.T, t cesium.T) cesium.T {
+				s := current.(summary)
+				n, _ := toNumber(t)
+				x := n.toFloat()
+
+				s.count++
+				delta := x - s.mean
+				s.mean += delta / float64(s.count)
+				s.m2 += delta * (x - s.mean)
+
+				return s
+			},
+		).
+		Map(func(t cesium.T) cesium.T {
+			s := t.(summary)
+			if s.count == 0 {
+				return Statistics{}
+			}
+
+			return Statistics{
+				Count:  s.count,
+				Mean:   s.mean,
+				StdDev: gomath.Sqrt(s.m2 / float64(s.count)),
+			}
+		})
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/commons/math"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestSum(t *testing.T) {
+	verifier.
+		Create(math.Sum(flux.Just(1, int8(2), uint16(3)))).
+		ExpectNext(int64(6)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestSumFloats(t *testing.T) {
+	verifier.
+		Create(math.Sum(flux.Just(1, 0.5))).
+		ExpectNext(1.5).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestSumEmpty(t *testing.T) {
+	verifier.
+		Create(math.Sum(flux.Empty())).
+		ExpectNext(int64(0)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestSumNonNumeric(t *testing.T) {
+	verifier.
+		Create(math.Sum(flux.Just(1, "2"))).
+		ThenRequest(1).
+		ExpectError(cesium.NonNumericItemError).
+		Verify(t)
+}
+
+func TestAverage(t *testing.T) {
+	verifier.
+		Create(math.Average(flux.Just(1, 2, 3, 4))).
+		ExpectNext(2.5).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestAverageEmpty(t *testing.T) {
+	verifier.
+		Create(math.Average(flux.Empty())).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMin(t *testing.T) {
+	verifier.
+		Create(math.Min(flux.Just(3, 1.5, 2))).
+		ExpectNext(1.5).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMax(t *testing.T) {
+	verifier.
+		Create(math.Max(flux.Just(3, 1.5, uint(7), 2))).
+		ExpectNext(uint(7)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMinEmpty(t *testing.T) {
+	verifier.
+		Create(math.Min(flux.Empty())).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestSummary(t *testing.T) {
+	verifier.
+		Create(math.Summary(flux.Just(2, 4, 4, 4, 5, 5, 7, 9))).
+		ExpectNext(math.Statistics{Count: 8, Mean: 5, StdDev: 2}).
+		ExpectComplete().
+		Verify(t)
+}
@@ -40,7 +40,8 @@ type T interface{}
 // shouldn't emit items to others. This is hard to achieve for hot Publishers
 // as it has to be implemented with a per subscription buffer inside the
 // publisher. This is why cesium does the trade-off of supporting only one
-// subscriber for hot observables.
+// subscriber for hot observables. To share a hot Publisher between multiple
+// subscribers, use Flux.Publish.
 type Publisher interface {
 	// Subscribe will subscribe the passed Subscriber to this Publisher and
 	// returns a Subscription that can also be used to control the Publisher or
@@ -89,6 +90,13 @@ type Processor interface {
 	Publisher
 }
 
+// FluxProcessor is a Processor that is also a Flux, so the reactive operators
+// can be applied to it.
+type FluxProcessor interface {
+	Flux
+	Subscriber
+}
+
 // Scheduler serves as a means to introduce multi-threading to reactive operators.
 // Observables/Publishers emit on the thread Subscribe was called on, so
 // to introduce multi-threading we execute everything on schedulers. Some
@@ -101,6 +109,42 @@ type Scheduler interface {
 	Schedule(action func(Canceller)) Cancellable
 }
 
+// TimedScheduler is a Scheduler that is also able to execute actions after a
+// delay. Operators working with time (like Flux.DelayElements) accept one, so
+// a virtual time scheduler (see verifier.VirtualTimeScheduler) can be passed
+// in tests instead of waiting for the real time to pass.
+type TimedScheduler interface {
+	Scheduler
+	Clock
+
+	// ScheduleAfter executes the action once the specified delay has passed.
+	// The same Canceller semantics as in Schedule apply.
+	ScheduleAfter(time.Duration, func(Canceller)) Cancellable
+}
+
+// Clock is the source of the current time for the operators working with time.
+// It is a part of TimedScheduler, so that the time can be made deterministic in
+// tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// TimestampedItem pairs an item with the time it was emitted at. It is emitted
+// by the Timestamp operator.
+type TimestampedItem struct {
+	Timestamp time.Time
+	Item      T
+}
+
+// ElapsedItem pairs an item with the time that elapsed since the previous
+// emission (or since the subscription, for the first item). It is emitted by
+// the Elapsed operator.
+type ElapsedItem struct {
+	Elapsed time.Duration
+	Item    T
+}
+
 // Cancellable is a way to cancel an action scheduled on a Scheduler.
 type Cancellable interface {
 	// Cancel the scheduled action.
@@ -118,25 +162,120 @@ type Canceller interface {
 	OnCancel(func())
 }
 
+// Disposable is a resource that can be released, like the connection of a
+// ConnectableFlux to its source.
+type Disposable interface {
+	// Dispose releases the resource.
+	Dispose()
+
+	// IsDisposed checks if the resource was already released.
+	IsDisposed() bool
+}
+
+// DisposableScheduler is a Scheduler owning resources, like the goroutines of a
+// worker pool, which are released once it is disposed. The actions scheduled
+// after that are not executed.
+type DisposableScheduler interface {
+	Scheduler
+	Disposable
+}
+
+// ConnectableFlux is a Flux that shares a single subscription to its source
+// between all of its subscribers. It does not subscribe to the source until
+// connected, which allows all the subscribers to subscribe first so none of
+// them misses any emissions. The source is requested at the pace of the
+// slowest subscriber.
+type ConnectableFlux interface {
+	Flux
+
+	// Connect subscribes to the source. The returned Disposable can be used to
+	// cancel the source subscription. Connecting an already connected
+	// ConnectableFlux returns the current connection. Once the source
+	// terminates or the connection is disposed, connecting again subscribes
+	// to the source anew.
+	Connect() Disposable
+
+	// AutoConnect returns a Flux that connects once it has the specified
+	// amount of subscribers. If the amount is zero or less, it connects
+	// immediately.
+	AutoConnect(int) Flux
+
+	// RefCount returns a Flux that connects once it has the specified amount
+	// of subscribers and disconnects when all of them are gone for the
+	// duration of the grace period.
+	RefCount(int, time.Duration) Flux
+}
+
+// ParallelFlux is a Flux split into rails, that are processed independently of
+// each other. The items of the source are distributed to the rails
+// round-robin. By default, the rails are processed on the goroutine emitting
+// the source items, use RunOn to process them in parallel.
+type ParallelFlux interface {
+	// Rails returns the number of rails.
+	Rails() int
+
+	// RunOn processes each of the rails on the Scheduler, so the rails run in
+	// parallel, up to the parallelism of the Scheduler.
+	RunOn(Scheduler) ParallelFlux
+
+	Map(func(T) T) ParallelFlux
+	Filter(func(T) bool) ParallelFlux
+	FlatMap(func(T) Publisher) ParallelFlux
+
+	// Reduce reduces the items of each rail and then the results of the rails
+	// into a single item.
+	Reduce(func(T, T) T) Mono
+
+	// Sequential merges the rails back into a Flux, in no particular order.
+	Sequential() Flux
+
+	// Ordered merges the rails back into a Flux by always emitting the least
+	// of the first items of the rails. If the items of each rail are ordered,
+	// so is the returned Flux.
+	Ordered(less func(T, T) bool) Flux
+}
+
 // Flux is a publisher with reactive operators that emits 0 to N elements, and
 // then completes (successfully or with an error).
 type Flux interface {
 	Publisher
 
 	Map(func(T) T) Flux
+	MapE(func(T) (T, error)) Flux
 	Handle(func(T, SynchronousSink)) Flux
 	Count() Mono
 	Reduce(func(T, T) T) Mono
 	Scan(func(T, T) T) Flux
+	ReduceWith(func() T, func(T, T) T) Mono
+	ScanWith(func() T, func(T, T) T) Flux
 	All(func(T) bool) Mono
 	Any(func(T) bool) Mono
 	HasElements() Mono
 	HasElement(T) Mono
 	Concat(Publisher /*<cesium.Publisher>*/) Flux
+	ConcatDelayError(Publisher /*<cesium.Publisher>*/) Flux
 	ConcatWith(...Publisher) Flux
 	FlatMap(func(T) Publisher, ...Scheduler) Flux
+	LimitRate(highTide int64, lowTide int64) Flux
+	LimitRequest(int64) Flux
+	DelayElements(time.Duration, ...TimedScheduler) Flux
+	DelaySubscription(time.Duration, ...TimedScheduler) Flux
+	DelaySubscriptionUntil(Publisher) Flux
+	Timestamp(...TimedScheduler) Flux /*<TimestampedItem>*/
+	Elapsed(...TimedScheduler) Flux   /*<ElapsedItem>*/
 	ToSlice() ([]T, error)
+	CollectSlice() Mono                        /*<[]T>*/
+	CollectSortedSlice(func(T, T) bool) Mono   /*<[]T>*/
+	CollectMap(func(T) T, func(T) T) Mono      /*<map[T]T>*/
+	CollectMultiMap(func(T) T, func(T) T) Mono /*<map[T][]T>*/
+	Collect(func() T, func(T, T)) Mono
 	ToChannel() (<-chan T, <-chan error)
+	Publish() ConnectableFlux
+	Replay(int) ConnectableFlux
+	Share() Flux
+	Cache() Flux
+	CacheWithTTL(time.Duration, ...TimedScheduler) Flux
+	Parallel(int) ParallelFlux
 
 	DoOnSubscribe(func(Subscription)) Flux
 	DoOnRequest(func(int64)) Flux
@@ -153,12 +292,15 @@ type Flux interface {
 	Dematerialize() Flux
 
 	Filter(func(T) bool) Flux
+	FilterWhen(func(T) Publisher /*<bool>*/, int) Flux
 	DistinctUntilChanged() Flux
 	Take(int64) Flux
 
 	OnErrorReturn(T) Flux
 	OnErrorResume(func(error) bool, Publisher) Flux
 	OnErrorMap(func(error) error) Flux
+	OnErrorContinue(func(error, T)) Flux
+	Checkpoint(string) Flux
 
 	BlockFirst() (T, bool, error)
 	BlockFirstTimeout(time.Duration) (T, bool, error)
@@ -172,13 +314,22 @@ type Mono interface {
 	Publisher
 
 	Map(func(T) T) Mono
+	MapE(func(T) (T, error)) Mono
 	FlatMap(fn func(T) Mono, scheduler ...Scheduler) Mono
 	FlatMapMany(fn func(T) Publisher, scheduler ...Scheduler) Flux
 	Handle(func(T, SynchronousSink)) Mono
 	ConcatWith(...Publisher) Flux
+	DelayElement(time.Duration, ...TimedScheduler) Mono
+	DelayUntil(func(T) Publisher) Mono
+	Timestamp(...TimedScheduler) Mono /*<TimestampedItem>*/
+	Elapsed(...TimedScheduler) Mono   /*<ElapsedItem>*/
 	ToChannel() (<-chan T, <-chan error)
+	Cache() Mono
+	CacheWithTTL(time.Duration, ...TimedScheduler) Mono
+	Share() Mono
 
 	Filter(func(T) bool) Mono
+	FilterWhen(func(T) Publisher /*<bool>*/) Mono
 
 	DoOnSubscribe(func(Subscription)) Mono
 	DoOnRequest(func(int64)) Mono
@@ -197,6 +348,7 @@ type Mono interface {
 	OnErrorReturn(T) Mono
 	OnErrorResume(func(error) bool, Mono) Mono //TODO: May return 2 items if Next -> Error from original???
 	OnErrorMap(func(error) error) Mono
+	Checkpoint(string) Mono
 
 	Block() (T, bool, error)
 	BlockTimeout(time.Duration) (T, bool, error)
@@ -265,6 +417,90 @@ type MonoSink interface {
 	OnDispose(func())
 }
 
+// EmitResult is the outcome of an emission attempt on a sink created by the
+// sinks package.
+type EmitResult int
+
+const (
+	// EmitResultOK means the signal was accepted.
+	EmitResultOK EmitResult = iota
+
+	// EmitResultFailTerminated means the sink has already terminated.
+	EmitResultFailTerminated
+
+	// EmitResultFailOverflow means the buffer of the sink is full because its
+	// subscribers did not request the items fast enough.
+	EmitResultFailOverflow
+
+	// EmitResultFailCancelled means the subscriber of the sink has cancelled.
+	EmitResultFailCancelled
+
+	// EmitResultFailNonSerialized means another signal was being emitted to
+	// the sink concurrently.
+	EmitResultFailNonSerialized
+)
+
+func (r EmitResult) String() string {
+	switch r {
+	case EmitResultOK:
+		return "OK"
+	case EmitResultFailTerminated:
+		return "FailTerminated"
+	case EmitResultFailOverflow:
+		return "FailOverflow"
+	case EmitResultFailCancelled:
+		return "FailCancelled"
+	case EmitResultFailNonSerialized:
+		return "FailNonSerialized"
+	}
+
+	return "Unknown"
+}
+
+// IsSuccess returns true if the signal was accepted.
+func (r EmitResult) IsSuccess() bool {
+	return r == EmitResultOK
+}
+
+// EmptySink is a sink that completes (or fails) without emitting any items,
+// viewed as a Mono. Unlike FluxSink, it is not bound to a single subscription.
+type EmptySink interface {
+	// Try to emit the complete signal.
+	TryEmitComplete() EmitResult
+
+	// Try to emit the error signal.
+	TryEmitError(error) EmitResult
+
+	// Returns a Mono view of the sink.
+	AsMono() Mono
+}
+
+// OneSink is a sink that emits at most one item, viewed as a Mono.
+type OneSink interface {
+	EmptySink
+
+	// Try to emit T and complete.
+	TryEmitNext(T) EmitResult
+}
+
+// ManySink is a sink that emits any number of items, viewed as a Flux. Unlike
+// FluxSink, it is not bound to a single subscription and reports the
+// concurrent emissions as EmitResultFailNonSerialized instead of corrupting
+// the emitted sequence.
+type ManySink interface {
+	// Try to emit T.
+	TryEmitNext(T) EmitResult
+
+	// Try to emit the complete signal.
+	TryEmitComplete() EmitResult
+
+	// Try to emit the error signal.
+	TryEmitError(error) EmitResult
+
+	// Returns a Flux view of the sink.
+	AsFlux() Flux
+}
+
 // SignalType represents a type of a signal emitted by a Publisher.
 type SignalType string
 
@@ -1,5 +1,11 @@
 package cesium
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 type err string
 
 func (e err) Error() string {
@@ -20,3 +26,118 @@ const NoEmissionOnSynchronousSinkError = err("No emissions received on the sink,
 // Flux.BlockFirstTimeout and Flux.BlockLastTimeout) when no matching items
 // would be emitted in the specified timeout duration.
 const TimeoutError = err("Timeout")
+
+// DisconnectedError is emitted to the subscribers of a ConnectableFlux when
+// its connection is disposed before the source terminates.
+const DisconnectedError = err("Disconnected")
+
+// SingleSubscriberError is emitted to the subscribers of a Publisher that
+// only supports a single subscriber (like processors.UnicastProcessor), except
+// for the first one. The publishers made from a channel emit it to the
+// subscribers that subscribe while another one is subscribed.
+const SingleSubscriberError = err("Only a single subscriber is allowed")
+
+// NonNumericItemError is emitted by the numeric aggregations in commons/math
+// when they receive an item that is not a number.
+const NonNumericItemError = err("Item is not a number")
+
+// CompositeError aggregates multiple errors into one. It is emitted by the
+// delay error operators (like Flux.ConcatDelayError) once all their sources
+// terminate. Both errors.Is and errors.As match against each of the causes.
+type CompositeError struct {
+	Errors []error
+}
+
+func (e *CompositeError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, cause := range e.Errors {
+		messages[i] = cause.Error()
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the aggregated errors.
+func (e *CompositeError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the aggregated errors matches the target.
+func (e *CompositeError) Is(target error) bool {
+	for _, cause := range e.Errors {
+		if errors.Is(cause, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the aggregated errors that matches the target, and if
+// so, sets the target to that error value and returns true.
+func (e *CompositeError) As(target interface{}) bool {
+	for _, cause := range e.Errors {
+		if errors.As(cause, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PanicError is emitted in place of a panic raised by a user supplied
+// function, like the mapper passed to Flux.Map or the generator passed to
+// flux.Generate. The upstream subscription of the failed operator is cancelled.
+type PanicError struct {
+	// Value is the value the function panicked with.
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value the function panicked with if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// AssemblyError wraps an error with the assembly trace of the pipeline it
+// passed through. The trace lists the checkpoints (see Flux.Checkpoint) and,
+// in the operator debug mode (see hooks.OnOperatorDebug), the operators along
+// with the call sites they were assembled at, from upstream to downstream.
+type AssemblyError struct {
+	Err   error
+	Trace []string
+}
+
+func (e *AssemblyError) Error() string {
+	return fmt.Sprintf("%v\nAssembly trace:\n\t%s", e.Err, strings.Join(e.Trace, "\n\t"))
+}
+
+// Unwrap returns the traced error.
+func (e *AssemblyError) Unwrap() error {
+	return e.Err
+}
+
+// NonPositiveRequestError is emitted to a subscriber that requests zero or a
+// negative amount of items, as required by the rule 3.9 of the Reactive
+// Streams specification. Its subscription is cancelled.
+const NonPositiveRequestError = err("Rule 3.9: the requested amount must be positive")
+
+// NilItemError is emitted to a subscriber in place of a nil item, which is
+// forbidden by the rule 2.13 of the Reactive Streams specification. The
+// subscription is cancelled.
+const NilItemError = err("Rule 2.13: items must not be nil")
+
+// UnrequestedItemError is emitted to a subscriber of a Publisher not created
+// by this library (see flux.From) when the Publisher emits more items than
+// requested, which is forbidden by the rule 1.1 of the Reactive Streams
+// specification. The Publisher is cancelled.
+const UnrequestedItemError = err("Rule 1.1: items must not be emitted without demand")
@@ -38,6 +38,14 @@ func FromSlice(slice []cesium.T) cesium.Flux {
 	return internal.FluxFromSlice(slice)
 }
 
+// From creates new cesium.Flux emitting the items of the supplied Publisher,
+// which can come from another library. Such Publisher is guarded by the
+// Reactive Streams rules, its signals are serialized and an item emitted
+// without demand terminates the Flux with cesium.UnrequestedItemError.
+func From(publisher cesium.Publisher) cesium.Flux {
+	return internal.FluxFrom(publisher)
+}
+
 // Range creates new cesium.Flux that emits 64bit integers from start to
 // (start + count).
 func Range(start int, count int) cesium.Flux {
@@ -87,7 +95,16 @@ func Error(err error) cesium.Flux {
 }
 
 // FromChannel creates a Flux from a channel that emits items from the channel
-// and completes when the channel closes.
+// and completes when the channel closes. It can be subscribed to by one
+// subscriber at a time, the others receive cesium.SingleSubscriberError. Once
+// the subscriber cancels, the next one continues with the following items.
 func FromChannel(c <-chan cesium.T) cesium.Flux {
 	return internal.FluxFromChannel(c)
 }
+
+// MergeDelayError creates a Flux that emits the items of all the supplied
+// publishers as they arrive. If any of them fails, the rest are still
+// consumed and the errors are emitted as a cesium.CompositeError at the end.
+func MergeDelayError(publishers ...cesium.Publisher) cesium.Flux {
+	return internal.FluxMergeDelayError(publishers...)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+)
+
+// benchmarkSubscriber requests batch items at a time, or an unbounded amount
+// if batch is zero, and signals done on termination.
+type benchmarkSubscriber struct {
+	batch        int64
+	received     int64
+	subscription cesium.Subscription
+	done         chan struct{}
+}
+
+func (s *benchmarkSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	s.subscription = subscription
+	if s.batch == 0 {
+		subscription.RequestUnbounded()
+	} else {
+		subscription.Request(s.batch)
+	}
+}
+
+func (s *benchmarkSubscriber) OnNext(cesium.T) {
+	s.received++
+	if s.batch != 0 && s.received%s.batch == 0 {
+		s.subscription.Request(s.batch)
+	}
+}
+
+func (s *benchmarkSubscriber) OnError(error) {
+	close(s.done)
+}
+
+func (s *benchmarkSubscriber) OnComplete() {
+	close(s.done)
+}
+
+// benchmarkRangeMapFilter measures the items per second flowing through a
+// Range().Map().Filter() chain, half of them being filtered out.
+func benchmarkRangeMapFilter(b *testing.B, batch int64) {
+	f := flux.Range(0, b.N).
+		Map(func(t cesium.T) cesium.T {
+			return t.(int64) + 1
+		}).
+		Filter(func(t cesium.T) bool {
+			return t.(int64)%2 == 0
+		})
+
+	s := &benchmarkSubscriber{batch: batch, done: make(chan struct{})}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	f.Subscribe(s)
+	<-s.done
+}
+
+func BenchmarkRangeMapFilterUnbounded(b *testing.B) {
+	benchmarkRangeMapFilter(b, 0)
+}
+
+func BenchmarkRangeMapFilterOneByOne(b *testing.B) {
+	benchmarkRangeMapFilter(b, 1)
+}
+
+func BenchmarkRangeMapFilterBatched(b *testing.B) {
+	benchmarkRangeMapFilter(b, 256)
+}
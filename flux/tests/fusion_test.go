@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestFusedChain(t *testing.T) {
+	f := flux.
+		FromSlice([]cesium.T{1, 1, 2, 2, 3, 4, 4, 5, 6}).
+		DistinctUntilChanged().
+		Map(func(a cesium.T) cesium.T {
+			return a.(int) * 10
+		}).
+		Filter(func(a cesium.T) bool {
+			return a.(int) != 30
+		}).
+		Handle(func(a cesium.T, sink cesium.SynchronousSink) {
+			if a.(int) == 60 {
+				sink.Complete()
+				return
+			}
+
+			sink.Next(a.(int) + 1)
+		})
+
+	verifier.
+		Create(f).
+		ExpectNext(11, 21, 41, 51).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFusedGenerate(t *testing.T) {
+	i := 0
+
+	f := flux.
+		Generate(func(sink cesium.SynchronousSink) {
+			if i == 6 {
+				sink.Complete()
+				return
+			}
+
+			sink.Next(i)
+			i++
+		}).
+		Filter(func(a cesium.T) bool {
+			return a.(int)%2 == 1
+		}).
+		Map(func(a cesium.T) cesium.T {
+			return a.(int) * a.(int)
+		})
+
+	verifier.
+		Create(f).
+		ExpectNext(1, 9, 25).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFusedMapPanic(t *testing.T) {
+	f := flux.
+		Range(0, 5).
+		Map(func(a cesium.T) cesium.T {
+			if a.(int64) == 2 {
+				panic("map")
+			}
+
+			return a
+		})
+
+	verifier.
+		Create(f).
+		ExpectNext(int64(0), int64(1)).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("map")).
+		Verify(t)
+}
+
+func TestFusedOnErrorContinue(t *testing.T) {
+	dropped := &droppedItems{}
+
+	f := flux.
+		Range(0, 5).
+		Filter(func(a cesium.T) bool {
+			if a.(int64)%2 == 1 {
+				panic("filter")
+			}
+
+			return true
+		}).
+		OnErrorContinue(dropped.add)
+
+	verifier.
+		Create(f).
+		ExpectNext(int64(0), int64(2), int64(4)).
+		ExpectComplete().
+		Then(func() {
+			dropped.expect(t, int64(1), int64(3))
+		}).
+		Verify(t)
+}
+
+func TestFusedHandleError(t *testing.T) {
+	err := errors.New("err")
+
+	f := flux.
+		Range(0, 5).
+		Handle(func(a cesium.T, sink cesium.SynchronousSink) {
+			if a.(int64) == 1 {
+				sink.Error(err)
+				return
+			}
+
+			sink.Next(a)
+		})
+
+	verifier.
+		Create(f).
+		ExpectNext(int64(0)).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestFusedResubscription(t *testing.T) {
+	f := flux.
+		Range(0, 4).
+		DistinctUntilChanged().
+		Map(func(a cesium.T) cesium.T {
+			return a.(int64) * 2
+		})
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(f).
+			ExpectNext(int64(0), int64(2), int64(4), int64(6)).
+			ExpectComplete().
+			Verify(t)
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCache(t *testing.T) {
+	mux := sync.Mutex{}
+	subscriptions := 0
+
+	publisher := flux.
+		Just(1, 2, 3).
+		DoOnSubscribe(func(cesium.Subscription) {
+			mux.Lock()
+			subscriptions++
+			mux.Unlock()
+		}).
+		Cache()
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 2, 3).
+		ExpectComplete().
+		Verify(t)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 2, 3).
+		ExpectComplete().
+		Verify(t)
+
+	mux.Lock()
+	if subscriptions != 1 {
+		t.Errorf("Expected one subscription to the source, got %v", subscriptions)
+	}
+	mux.Unlock()
+}
+
+func TestCacheError(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		Just(1).
+		ConcatWith(flux.Error(err)).
+		Cache()
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectError(err).
+		Verify(t)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestCacheWithTTL(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	c := make(chan cesium.T, 2)
+
+	publisher := flux.
+		FromChannel(c).
+		CacheWithTTL(time.Second, scheduler)
+
+	c <- 1
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ThenCancel().
+		Verify(t)
+
+	scheduler.AdvanceTimeBy(time.Second * 2)
+	c <- 2
+	close(c)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(2).
+		ExpectComplete().
+		Verify(t)
+}
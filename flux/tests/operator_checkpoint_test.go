@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/hooks"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func assemblyTrace(err error) []string {
+	var assemblyErr *cesium.AssemblyError
+	if !errors.As(err, &assemblyErr) {
+		return nil
+	}
+
+	return assemblyErr.Trace
+}
+
+func TestCheckpoint(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		Error(err).
+		Checkpoint("source").
+		Map(func(a cesium.T) cesium.T {
+			return a
+		}).
+		Checkpoint("mapped")
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(func(e error) bool {
+			trace := assemblyTrace(e)
+			return errors.Unwrap(e) == err &&
+				len(trace) == 2 &&
+				trace[0] == `checkpoint("source")` &&
+				trace[1] == `checkpoint("mapped")`
+		}).
+		Verify(t)
+}
+
+func TestCheckpointPassesItems(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		Checkpoint("source")
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 2, 3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestOperatorDebug(t *testing.T) {
+	hooks.OnOperatorDebug()
+	defer hooks.ResetOnOperatorDebug()
+
+	err := errors.New("err")
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return nil, err
+		}).
+		Map(func(a cesium.T) cesium.T {
+			return a
+		}).
+		Checkpoint("mapped")
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(func(e error) bool {
+			trace := assemblyTrace(e)
+			if !errors.Is(e, err) || len(trace) != 3 {
+				t.Logf("Unexpected trace %v", trace)
+				return false
+			}
+
+			return strings.HasPrefix(trace[0], "Flux.MapE at ") &&
+				strings.HasPrefix(trace[1], "Flux.Map at ") &&
+				strings.HasPrefix(trace[2], `checkpoint("mapped") at `) &&
+				strings.Contains(trace[0], "operator_checkpoint_test.go")
+		}).
+		Verify(t)
+}
+
+func TestOperatorDebugDisabled(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return nil, err
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
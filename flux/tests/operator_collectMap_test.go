@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCollectMap(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		CollectMap(
+			func(t cesium.T) cesium.T {
+				return t.(int) % 2
+			},
+			func(t cesium.T) cesium.T {
+				return t.(int) * 10
+			},
+		)
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return reflect.DeepEqual(t, map[cesium.T]cesium.T{0: 20, 1: 30})
+		}).
+		ExpectComplete().
+		Verify(t)
+}
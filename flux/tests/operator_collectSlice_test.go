@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCollectSlice(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		CollectSlice()
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return reflect.DeepEqual(t, []cesium.T{1, 2, 3})
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestCollectSliceEmpty(t *testing.T) {
+	publisher := flux.
+		Empty().
+		CollectSlice()
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return reflect.DeepEqual(t, []cesium.T{})
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestCollectSliceError(t *testing.T) {
+	err := errors.New("x")
+	publisher := flux.
+		Error(err).
+		CollectSlice()
+
+	verifier.
+		Create(publisher).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestCollectSliceSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements != 1 {
+			return nil
+		}
+
+		return flux.Range(0, 3).CollectSlice()
+	})
+}
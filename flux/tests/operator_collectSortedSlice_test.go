@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCollectSortedSlice(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{3, 1, 2}).
+		CollectSortedSlice(func(a cesium.T, b cesium.T) bool {
+			return a.(int) < b.(int)
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return reflect.DeepEqual(t, []cesium.T{1, 2, 3})
+		}).
+		ExpectComplete().
+		Verify(t)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCollect(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{"a", "b", "c"}).
+		Collect(
+			func() cesium.T {
+				return &strings.Builder{}
+			},
+			func(container cesium.T, t cesium.T) {
+				container.(*strings.Builder).WriteString(t.(string))
+			},
+		)
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return t.(*strings.Builder).String() == "abc"
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestCollectSupplierPerSubscription(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{"a", "b"}).
+		Collect(
+			func() cesium.T {
+				return &strings.Builder{}
+			},
+			func(container cesium.T, t cesium.T) {
+				container.(*strings.Builder).WriteString(t.(string))
+			},
+		)
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(publisher).
+			ExpectNextMatches(func(t cesium.T) bool {
+				return t.(*strings.Builder).String() == "ab"
+			}).
+			ExpectComplete().
+			Verify(t)
+	}
+}
+
+func TestCollectSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements != 1 {
+			return nil
+		}
+
+		return flux.
+			FromSlice([]cesium.T{"a", "b"}).
+			Collect(
+				func() cesium.T {
+					return &strings.Builder{}
+				},
+				func(container cesium.T, t cesium.T) {
+					container.(*strings.Builder).WriteString(t.(string))
+				},
+			)
+	})
+}
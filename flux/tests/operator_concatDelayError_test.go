@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+type codeError struct {
+	code int
+}
+
+func (e codeError) Error() string {
+	return "code error"
+}
+
+func TestConcatDelayError(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2}).
+		ConcatDelayError(flux.FromSlice([]cesium.T{
+			flux.FromSlice([]cesium.T{3, 4}),
+			flux.FromSlice([]cesium.T{5, 6}),
+		}))
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 2, 3, 4, 5, 6).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestConcatDelayErrorOnErrorInSubPublishers(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := codeError{code: 2}
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2}).
+		ConcatDelayError(flux.FromSlice([]cesium.T{
+			flux.Error(err1),
+			flux.FromSlice([]cesium.T{3, 4}),
+			flux.Error(err2),
+			flux.FromSlice([]cesium.T{5, 6}),
+		}))
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 2, 3, 4, 5, 6).
+		ExpectErrorMatches(func(err error) bool {
+			var composite *cesium.CompositeError
+			if !errors.As(err, &composite) || len(composite.Errors) != 2 {
+				return false
+			}
+
+			var ce codeError
+			return errors.Is(err, err1) && errors.As(err, &ce) && ce.code == 2
+		}).
+		Verify(t)
+}
+
+func TestConcatDelayErrorOnErrorInSource(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		Error(err).
+		ConcatDelayError(flux.FromSlice([]cesium.T{
+			flux.FromSlice([]cesium.T{1, 2}),
+		}))
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 2).
+		ExpectErrorMatches(func(e error) bool {
+			return errors.Is(e, err)
+		}).
+		Verify(t)
+}
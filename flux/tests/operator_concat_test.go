@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"errors"
+	"math"
 
 	"github.com/DusanKasan/cesium"
 	"github.com/DusanKasan/cesium/flux"
@@ -41,3 +42,19 @@ func TestConcatOnErrorInSubPublisher(t *testing.T) {
 		ExpectError(err).
 		Verify(t)
 }
+
+func TestConcatUnbounded(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2}).
+		Concat(flux.FromSlice([]cesium.T{
+			flux.FromSlice([]cesium.T{3, 4}),
+			flux.FromSlice([]cesium.T{5, 6}),
+		}))
+
+	verifier.
+		Create(publisher).
+		ThenRequest(math.MaxInt64).
+		ExpectNextCount(6).
+		ExpectComplete().
+		Verify(t)
+}
@@ -212,6 +212,27 @@ func TestCreateWithBufferUnbounded(t *testing.T) {
 		Verify(t)
 }
 
+func TestCreateWithBufferRequestAfterUnbounded(t *testing.T) {
+	in := make(chan bool)
+
+	publisher := flux.Create(func(s cesium.FluxSink) {
+		<-in
+		s.Next(1)
+		s.Next(2)
+		s.Next(3)
+		s.Complete()
+	}, flux.OverflowStrategyBuffer)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(math.MaxInt64).
+		ThenRequest(1).
+		Then(func() { in <- true }).
+		ExpectNextCount(3).
+		ExpectComplete().
+		Verify(t)
+}
+
 func TestCreateWithErrorUnbounded(t *testing.T) {
 	in := make(chan bool)
 
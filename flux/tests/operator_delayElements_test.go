@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"errors"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestDelayElements(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	f := flux.Just(1, 2, 3).DelayElements(time.Second, scheduler)
+
+	verifier.
+		Create(f).
+		ThenRequest(3).
+		ThenAwait(time.Millisecond * 10).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Millisecond * 500)
+		}).
+		ExpectNextCount(0).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Millisecond * 500)
+		}).
+		ExpectNext(1).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNext(2).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNext(3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelayElementsRealTime(t *testing.T) {
+	f := flux.Just(1, 2).DelayElements(time.Millisecond * 5)
+
+	verifier.
+		Create(f).
+		ExpectNext(1, 2).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelayElementsPropagatesErrorImmediately(t *testing.T) {
+	err := errors.New("err")
+	scheduler := verifier.NewVirtualTimeScheduler()
+	f := flux.Error(err).DelayElements(time.Second, scheduler)
+
+	verifier.
+		Create(f).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestDelayElementsSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		return flux.
+			Range(0, int(elements)).
+			DelayElements(time.Millisecond)
+	})
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"errors"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestDelaySubscription(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	subscribed := make(chan bool, 1)
+
+	f := flux.Just(1, 2).
+		DoOnSubscribe(func(cesium.Subscription) {
+			subscribed <- true
+		}).
+		DelaySubscription(time.Second, scheduler)
+
+	verifier.
+		Create(f).
+		ThenRequest(2).
+		ThenAwait(time.Millisecond*10).
+		Then(func() {
+			if len(subscribed) != 0 {
+				t.Errorf("Subscribed to the source before the delay passed")
+			}
+
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNext(1, 2).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelaySubscriptionUntil(t *testing.T) {
+	c := make(chan cesium.T)
+
+	f := flux.Just(1, 2).DelaySubscriptionUntil(flux.FromChannel(c))
+
+	verifier.
+		Create(f).
+		ThenRequest(2).
+		ThenAwait(time.Millisecond*10).
+		ExpectNextCount(0).
+		Then(func() {
+			c <- true
+		}).
+		ExpectNext(1, 2).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelaySubscriptionUntilOnError(t *testing.T) {
+	err := errors.New("err")
+
+	f := flux.Just(1, 2).DelaySubscriptionUntil(mono.Error(err))
+
+	verifier.
+		Create(f).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestElapsed(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	f := flux.Just(1, 2, 3).
+		DelayElements(time.Second, scheduler).
+		Elapsed(scheduler)
+
+	elapsed := func(item int, d time.Duration) func(cesium.T) bool {
+		return func(t cesium.T) bool {
+			e := t.(cesium.ElapsedItem)
+			return e.Item == item && e.Elapsed == d
+		}
+	}
+
+	verifier.
+		Create(f).
+		ThenRequest(3).
+		ThenAwait(time.Millisecond * 10).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second * 3)
+		}).
+		ExpectNextMatches(elapsed(1, time.Second)).
+		ExpectNextMatches(elapsed(2, time.Second)).
+		ExpectNextMatches(elapsed(3, time.Second)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestElapsedScalarFlux(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	f := flux.Just(1).
+		Elapsed(scheduler).
+		Map(func(t cesium.T) cesium.T {
+			return t
+		})
+
+	// The item is emitted upon request, a second after the subscription.
+	verifier.
+		Create(f).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNextMatches(func(t cesium.T) bool {
+			e := t.(cesium.ElapsedItem)
+			return e.Item == 1 && e.Elapsed == time.Second
+		}).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
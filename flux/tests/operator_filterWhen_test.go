@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"testing"
+
+	"errors"
+
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestFilterWhen(t *testing.T) {
+	f := flux.Just(2, 30, 22, 5, 60, 1).
+		FilterWhen(func(a cesium.T) cesium.Publisher {
+			return mono.Just(a.(int) > 10)
+		}, 3)
+
+	verifier.
+		Create(f).
+		ExpectNext(30, 22, 60).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFilterWhenEmptyPredicateRejects(t *testing.T) {
+	f := flux.Just(1, 2, 3).
+		FilterWhen(func(a cesium.T) cesium.Publisher {
+			if a.(int) == 2 {
+				return mono.Empty()
+			}
+
+			return mono.Just(true)
+		}, 1)
+
+	verifier.
+		Create(f).
+		ExpectNext(1, 3).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFilterWhenPredicateError(t *testing.T) {
+	err := errors.New("err")
+
+	f := flux.Just(1, 2, 3).
+		FilterWhen(func(a cesium.T) cesium.Publisher {
+			if a.(int) == 2 {
+				return mono.Error(err)
+			}
+
+			return mono.Just(true)
+		}, 1)
+
+	verifier.
+		Create(f).
+		ExpectNext(1).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestFilterWhenPredicatesResolvingOutOfOrder(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	// The predicates of the later items resolve sooner, the order of the
+	// items must be kept nevertheless.
+	f := flux.Just(1, 2, 3, 4).
+		FilterWhen(func(a cesium.T) cesium.Publisher {
+			delay := time.Duration(5-a.(int)) * time.Second
+			return mono.Just(a.(int) != 2).DelayElement(delay, scheduler)
+		}, 4)
+
+	verifier.
+		Create(f).
+		ThenRequest(3).
+		ThenAwait(time.Millisecond*10).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second * 3)
+		}).
+		ExpectNextCount(0).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNext(1, 3, 4).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFilterWhenSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		return flux.
+			Range(0, int(elements)).
+			FilterWhen(func(cesium.T) cesium.Publisher {
+				return mono.Just(true)
+			}, 4)
+	})
+}
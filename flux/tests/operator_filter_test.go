@@ -71,3 +71,22 @@ func TestFilterAsyncSource(t *testing.T) {
 		ExpectComplete().
 		Verify(t)
 }
+
+// The filter requests a replacement for each rejected item from OnNext, which
+// must not recurse into the source.
+func TestFilterLongRange(t *testing.T) {
+	f := flux.Range(0, 1000000).
+		Map(func(a cesium.T) cesium.T {
+			return a
+		}).
+		Filter(func(a cesium.T) bool {
+			return a.(int64)%250000 == 0
+		})
+
+	verifier.
+		Create(f).
+		ExpectNext(int64(0), int64(250000), int64(500000), int64(750000)).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
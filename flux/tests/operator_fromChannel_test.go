@@ -45,3 +45,42 @@ func TestFromChannelUnbounded(t *testing.T) {
 		ExpectComplete().
 		Verify(t)
 }
+
+func TestFromChannelResubscribeAfterCancel(t *testing.T) {
+	c := make(chan cesium.T, 4)
+	c <- 1
+	c <- 2
+	c <- 3
+	c <- 4
+	close(c)
+
+	f := flux.FromChannel(c)
+
+	verifier.
+		Create(f).
+		ExpectNext(1, 2).
+		ThenCancel().
+		Verify(t)
+
+	verifier.
+		Create(f).
+		ExpectNext(3, 4).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromChannelSingleSubscriber(t *testing.T) {
+	c := make(chan cesium.T)
+	f := flux.FromChannel(c)
+
+	f.Subscribe(&noopSubscriber{})
+
+	verifier.
+		Create(f).
+		ThenRequest(1).
+		ExpectError(cesium.SingleSubscriberError).
+		Verify(t)
+
+	close(c)
+}
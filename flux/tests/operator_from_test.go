@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+// slicePublisher is a naive Publisher implemented without the library. It
+// emits the items synchronously from Request, recursing if more items are
+// requested from OnNext.
+type slicePublisher []cesium.T
+
+func (p slicePublisher) Subscribe(s cesium.Subscriber) cesium.Subscription {
+	sub := &sliceSubscription{items: p, subscriber: s}
+	s.OnSubscribe(sub)
+	return sub
+}
+
+type sliceSubscription struct {
+	items      []cesium.T
+	subscriber cesium.Subscriber
+	index      int
+	done       bool
+}
+
+func (s *sliceSubscription) Request(n int64) {
+	for ; n > 0 && !s.done && s.index < len(s.items); n-- {
+		s.index++
+		s.subscriber.OnNext(s.items[s.index-1])
+	}
+
+	if !s.done && s.index == len(s.items) {
+		s.done = true
+		s.subscriber.OnComplete()
+	}
+}
+
+func (s *sliceSubscription) RequestUnbounded() {
+	s.Request(math.MaxInt64)
+}
+
+func (s *sliceSubscription) Cancel() {
+	s.done = true
+}
+
+// eagerPublisher emits its items on subscription, ignoring the demand.
+type eagerPublisher []cesium.T
+
+func (p eagerPublisher) Subscribe(s cesium.Subscriber) cesium.Subscription {
+	sub := &sliceSubscription{}
+	s.OnSubscribe(sub)
+	for _, item := range p {
+		s.OnNext(item)
+	}
+	s.OnComplete()
+
+	return sub
+}
+
+func TestFrom(t *testing.T) {
+	f := flux.From(slicePublisher{1, 2, 3})
+
+	verifier.
+		Create(f).
+		ExpectNext(1, 2, 3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromOperators(t *testing.T) {
+	f := flux.
+		From(slicePublisher{1, 2, 3}).
+		Map(func(t cesium.T) cesium.T {
+			return t.(int) * 2
+		}).
+		Filter(func(t cesium.T) bool {
+			return t.(int) > 2
+		})
+
+	verifier.
+		Create(f).
+		ExpectNext(4, 6).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromItemWithoutDemand(t *testing.T) {
+	f := flux.From(eagerPublisher{1, 2, 3})
+
+	verifier.
+		Create(f).
+		ExpectError(cesium.UnrequestedItemError).
+		Verify(t)
+}
+
+func TestFromPanic(t *testing.T) {
+	f := flux.From(panickingPublisher{})
+
+	verifier.
+		Create(f).
+		ExpectErrorMatches(isPanicWith("publisher")).
+		Verify(t)
+}
+
+type panickingPublisher struct{}
+
+func (panickingPublisher) Subscribe(cesium.Subscriber) cesium.Subscription {
+	panic("publisher")
+}
+
+func TestForeignPublishersInOperators(t *testing.T) {
+	err := errors.New("err")
+
+	concat := flux.
+		Just(1).
+		ConcatWith(slicePublisher{2, 3})
+
+	verifier.
+		Create(concat).
+		ExpectNext(1, 2, 3).
+		ExpectComplete().
+		Verify(t)
+
+	flatMap := flux.
+		Just(1).
+		FlatMap(func(t cesium.T) cesium.Publisher {
+			return slicePublisher{t, t}
+		})
+
+	verifier.
+		Create(flatMap).
+		ExpectNext(1, 1).
+		ExpectComplete().
+		Verify(t)
+
+	resume := flux.
+		Error(err).
+		OnErrorResume(
+			func(e error) bool {
+				return e == err
+			},
+			slicePublisher{4})
+
+	verifier.
+		Create(resume).
+		ExpectNext(4).
+		ExpectComplete().
+		Verify(t)
+
+	deferred := flux.Defer(func() cesium.Publisher {
+		return slicePublisher{5}
+	})
+
+	verifier.
+		Create(deferred).
+		ExpectNext(5).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		items := make(slicePublisher, elements)
+		for i := range items {
+			items[i] = i
+		}
+
+		return flux.From(items)
+	})
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestLimitRate(t *testing.T) {
+	mux := sync.Mutex{}
+	var requests []int64
+
+	publisher := flux.
+		Range(0, 10).
+		DoOnRequest(func(n int64) {
+			mux.Lock()
+			requests = append(requests, n)
+			mux.Unlock()
+		}).
+		LimitRate(4, 2)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(100).
+		ExpectNextCount(10).
+		ExpectComplete().
+		Verify(t)
+
+	mux.Lock()
+	if !reflect.DeepEqual(requests, []int64{4, 2, 2, 2, 2, 2}) {
+		t.Errorf("Expected the upstream requested in batches of [4 2 2 2 2 2], got %v", requests)
+	}
+	mux.Unlock()
+}
+
+func TestLimitRateSlowSubscriber(t *testing.T) {
+	mux := sync.Mutex{}
+	requested := int64(0)
+
+	publisher := flux.
+		Range(0, 1000).
+		DoOnRequest(func(n int64) {
+			mux.Lock()
+			requested += n
+			mux.Unlock()
+		}).
+		LimitRate(10, 0)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectNextCount(1).
+		ThenAwait(time.Millisecond * 20).
+		ThenCancel().
+		Verify(t)
+
+	mux.Lock()
+	if requested != 10 {
+		t.Errorf("Expected 10 items requested from upstream, got %v", requested)
+	}
+	mux.Unlock()
+}
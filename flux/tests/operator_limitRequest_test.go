@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestLimitRequest(t *testing.T) {
+	mux := sync.Mutex{}
+	requested := int64(0)
+
+	publisher := flux.
+		Range(0, 100).
+		DoOnRequest(func(n int64) {
+			mux.Lock()
+			requested += n
+			mux.Unlock()
+		}).
+		LimitRequest(3)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(math.MaxInt64).
+		ExpectNext(int64(0), int64(1), int64(2)).
+		ExpectComplete().
+		Verify(t)
+
+	mux.Lock()
+	if requested != 3 {
+		t.Errorf("Expected 3 items requested from upstream, got %v", requested)
+	}
+	mux.Unlock()
+}
+
+func TestLimitRequestZero(t *testing.T) {
+	verifier.
+		Create(flux.Range(0, 100).LimitRequest(0)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestLimitRequestShorterSource(t *testing.T) {
+	verifier.
+		Create(flux.Just(1, 2).LimitRequest(5)).
+		ExpectNext(1, 2).
+		ExpectComplete().
+		Verify(t)
+}
+
+// signalRecorder records the types of the received signals, requesting one
+// item upon subscription.
+type signalRecorder struct {
+	mux     sync.Mutex
+	signals []string
+	done    chan struct{}
+}
+
+func (r *signalRecorder) record(signal string) {
+	r.mux.Lock()
+	r.signals = append(r.signals, signal)
+	r.mux.Unlock()
+}
+
+func (r *signalRecorder) OnSubscribe(subscription cesium.Subscription) {
+	r.record("subscribe")
+	subscription.Request(1)
+}
+
+func (r *signalRecorder) OnNext(cesium.T) {
+	r.record("next")
+}
+
+func (r *signalRecorder) OnError(error) {
+	r.record("error")
+	close(r.done)
+}
+
+func (r *signalRecorder) OnComplete() {
+	r.record("complete")
+	close(r.done)
+}
+
+func TestLimitRequestNonPositiveSignalOrder(t *testing.T) {
+	for _, n := range []int64{0, -1} {
+		r := &signalRecorder{done: make(chan struct{})}
+		flux.Range(0, 5).LimitRequest(n).Subscribe(r)
+
+		select {
+		case <-r.done:
+		case <-time.After(time.Millisecond * 100):
+			t.Fatalf("LimitRequest(%v) did not terminate", n)
+		}
+
+		r.mux.Lock()
+		if expected := []string{"subscribe", "complete"}; !reflect.DeepEqual(r.signals, expected) {
+			t.Errorf("Wrong signals for LimitRequest(%v). Expected: %v, Got: %v", n, expected, r.signals)
+		}
+		r.mux.Unlock()
+	}
+}
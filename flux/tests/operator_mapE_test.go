@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestMapE(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return 10 * a.(int), nil
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(10, 20, 30).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMapEError(t *testing.T) {
+	err := errors.New("err")
+	cancelled := make(chan bool, 1)
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			if a.(int) == 2 {
+				return nil, err
+			}
+
+			return a, nil
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ThenRequest(1).
+		ExpectError(err).
+		Then(func() {
+			select {
+			case <-cancelled:
+			case <-time.After(time.Millisecond * 100):
+				t.Errorf("Upstream not cancelled")
+			}
+		}).
+		Verify(t)
+}
+
+func TestMapEScalar(t *testing.T) {
+	publisher := flux.
+		Just(1).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return 10 * a.(int), nil
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(10).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMapEScalarError(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		Just(1).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return nil, err
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectError(err).
+		Verify(t)
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestMergeDelayError(t *testing.T) {
+	publisher := flux.MergeDelayError(
+		flux.FromSlice([]cesium.T{1, 2}),
+		flux.FromSlice([]cesium.T{3, 4}),
+	)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(4).
+		ExpectNextCount(4).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMergeDelayErrorOnError(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+
+	publisher := flux.MergeDelayError(
+		flux.Error(err1),
+		flux.FromSlice([]cesium.T{1, 2}),
+		flux.Error(err2),
+		flux.FromSlice([]cesium.T{3, 4}),
+	)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(4).
+		ExpectNextCount(4).
+		ExpectErrorMatches(func(err error) bool {
+			return errors.Is(err, err1) && errors.Is(err, err2)
+		}).
+		Verify(t)
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+type droppedItems struct {
+	mux   sync.Mutex
+	items []cesium.T
+	errs  []error
+}
+
+func (d *droppedItems) add(err error, item cesium.T) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.items = append(d.items, item)
+	d.errs = append(d.errs, err)
+}
+
+func (d *droppedItems) expect(t *testing.T, items ...cesium.T) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if !reflect.DeepEqual(d.items, items) {
+		t.Errorf("Expected dropped items %v, got %v", items, d.items)
+	}
+}
+
+func TestOnErrorContinueMapE(t *testing.T) {
+	err := errors.New("err")
+	dropped := &droppedItems{}
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3, 4, 5}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			if a.(int)%2 == 0 {
+				return nil, err
+			}
+
+			return a, nil
+		}).
+		OnErrorContinue(dropped.add)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 3, 5).
+		ExpectComplete().
+		Then(func() {
+			dropped.expect(t, 2, 4)
+			for _, e := range dropped.errs {
+				if e != err {
+					t.Errorf("Expected %v, got %v", err, e)
+				}
+			}
+		}).
+		Verify(t)
+}
+
+func TestOnErrorContinueMapPanic(t *testing.T) {
+	dropped := &droppedItems{}
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		Map(func(a cesium.T) cesium.T {
+			if a.(int) == 2 {
+				panic("boom")
+			}
+
+			return a
+		}).
+		OnErrorContinue(dropped.add)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1, 3).
+		ExpectComplete().
+		Then(func() {
+			dropped.expect(t, 2)
+			if !isPanicWith("boom")(dropped.errs[0]) {
+				t.Errorf("Expected panic error, got %v", dropped.errs[0])
+			}
+		}).
+		Verify(t)
+}
+
+func TestOnErrorContinueFilterPanic(t *testing.T) {
+	dropped := &droppedItems{}
+
+	publisher := flux.
+		Range(1, 4).
+		Filter(func(a cesium.T) bool {
+			if a.(int64) == 2 {
+				panic("boom")
+			}
+
+			return true
+		}).
+		OnErrorContinue(dropped.add)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(1), int64(3), int64(4)).
+		ExpectComplete().
+		Then(func() {
+			dropped.expect(t, int64(2))
+		}).
+		Verify(t)
+}
+
+func TestOnErrorContinueHandle(t *testing.T) {
+	dropped := &droppedItems{}
+
+	publisher := flux.
+		FromSlice([]cesium.T{"1", "x", "3"}).
+		Handle(func(a cesium.T, sink cesium.SynchronousSink) {
+			if a.(string) == "x" {
+				sink.Error(errors.New("not a number"))
+				return
+			}
+
+			sink.Next(a)
+		}).
+		OnErrorContinue(dropped.add)
+
+	verifier.
+		Create(publisher).
+		ExpectNext("1", "3").
+		ExpectComplete().
+		Then(func() {
+			dropped.expect(t, "x")
+		}).
+		Verify(t)
+}
+
+func TestOnErrorContinueThroughOtherOperators(t *testing.T) {
+	dropped := &droppedItems{}
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		Map(func(a cesium.T) cesium.T {
+			if a.(int) == 2 {
+				panic("boom")
+			}
+
+			return a
+		}).
+		DoOnNext(func(a cesium.T) {}).
+		Map(func(a cesium.T) cesium.T {
+			return 10 * a.(int)
+		}).
+		OnErrorContinue(dropped.add)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(10, 30).
+		ExpectComplete().
+		Then(func() {
+			dropped.expect(t, 2)
+		}).
+		Verify(t)
+}
+
+func TestOnErrorContinueDoesNotAffectDownstream(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		OnErrorContinue(func(error, cesium.T) {}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			if a.(int) == 2 {
+				return nil, err
+			}
+
+			return a, nil
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/schedulers"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func lessInt64(a cesium.T, b cesium.T) bool {
+	return a.(int64) < b.(int64)
+}
+
+func TestParallelSequential(t *testing.T) {
+	var expected []cesium.T
+	for i := int64(0); i < 1000; i++ {
+		if i%3 == 0 {
+			expected = append(expected, i*2)
+		}
+	}
+
+	publisher := flux.
+		Range(0, 1000).
+		Parallel(4).
+		RunOn(schedulers.Parallel()).
+		Filter(func(t cesium.T) bool {
+			return t.(int64)%3 == 0
+		}).
+		Map(func(t cesium.T) cesium.T {
+			return t.(int64) * 2
+		}).
+		Sequential().
+		CollectSortedSlice(lessInt64)
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return reflect.DeepEqual(t, expected)
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestParallelOrdered(t *testing.T) {
+	pool := schedulers.NewWorkerPool(4)
+	defer pool.Dispose()
+
+	var expected []cesium.T
+	for i := int64(0); i < 1000; i++ {
+		expected = append(expected, i+1)
+	}
+
+	publisher := flux.
+		Range(0, 1000).
+		Parallel(4).
+		RunOn(pool).
+		Map(func(t cesium.T) cesium.T {
+			return t.(int64) + 1
+		}).
+		Ordered(lessInt64).
+		CollectSlice()
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			return reflect.DeepEqual(t, expected)
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestParallelReduce(t *testing.T) {
+	publisher := flux.
+		Range(1, 100).
+		Parallel(3).
+		RunOn(schedulers.Parallel()).
+		Reduce(func(a cesium.T, b cesium.T) cesium.T {
+			return a.(int64) + b.(int64)
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(5050)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestParallelFlatMap(t *testing.T) {
+	publisher := flux.
+		Just(1, 2, 3).
+		Parallel(2).
+		FlatMap(func(t cesium.T) cesium.Publisher {
+			return flux.Just(t, t)
+		}).
+		Sequential().
+		Count()
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(6)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestParallelRunsRailsInParallel(t *testing.T) {
+	pool := schedulers.NewWorkerPool(4)
+	defer pool.Dispose()
+
+	var inFlight, maxInFlight int32
+
+	publisher := flux.
+		Range(0, 8).
+		Parallel(4).
+		RunOn(pool).
+		Map(func(t cesium.T) cesium.T {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond * 20)
+			atomic.AddInt32(&inFlight, -1)
+			return t
+		}).
+		Sequential()
+
+	verifier.
+		Create(publisher).
+		AndTimeout(time.Second).
+		ThenRequest(8).
+		ExpectNextCount(8).
+		ExpectComplete().
+		Verify(t)
+
+	if atomic.LoadInt32(&maxInFlight) < 2 {
+		t.Errorf("Rails were not processed in parallel")
+	}
+}
+
+func TestParallelBackpressure(t *testing.T) {
+	cancelled := make(chan bool, 1)
+
+	publisher := flux.
+		Range(0, 1000000).
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		Parallel(4).
+		RunOn(schedulers.Parallel()).
+		Sequential()
+
+	verifier.
+		Create(publisher).
+		ThenRequest(2).
+		ExpectNextCount(2).
+		ThenCancel().
+		Then(func() {
+			select {
+			case <-cancelled:
+			case <-time.After(time.Millisecond * 100):
+				t.Errorf("Source not cancelled")
+			}
+		}).
+		Verify(t)
+}
+
+func TestParallelError(t *testing.T) {
+	err := codeError{code: 1}
+
+	publisher := flux.
+		Error(err).
+		Parallel(2).
+		RunOn(schedulers.Parallel()).
+		Sequential()
+
+	verifier.
+		Create(publisher).
+		ExpectErrorMatches(func(e error) bool {
+			return e == err
+		}).
+		Verify(t)
+}
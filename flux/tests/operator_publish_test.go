@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func verifyConcurrently(t *testing.T, verifiers ...*verifier.StepVerifier) {
+	wg := sync.WaitGroup{}
+	for _, v := range verifiers {
+		wg.Add(1)
+		go func(v *verifier.StepVerifier) {
+			defer wg.Done()
+			v.Verify(t)
+		}(v)
+	}
+
+	wg.Wait()
+}
+
+func TestPublishConnect(t *testing.T) {
+	c := make(chan cesium.T)
+	publisher := flux.FromChannel(c).Publish()
+
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		publisher.Connect()
+		c <- 1
+		c <- 2
+		close(c)
+	}()
+
+	verifyConcurrently(
+		t,
+		verifier.Create(publisher).ExpectNext(1, 2).ExpectComplete(),
+		verifier.Create(publisher).ExpectNext(1, 2).ExpectComplete(),
+	)
+}
+
+func TestPublishAutoConnect(t *testing.T) {
+	mux := sync.Mutex{}
+	subscriptions := 0
+
+	publisher := flux.
+		Just(1, 2, 3).
+		DoOnSubscribe(func(cesium.Subscription) {
+			mux.Lock()
+			subscriptions++
+			mux.Unlock()
+		}).
+		Publish().
+		AutoConnect(2)
+
+	verifyConcurrently(
+		t,
+		verifier.Create(publisher).ExpectNext(1, 2, 3).ExpectComplete(),
+		verifier.Create(publisher).ExpectNext(1, 2, 3).ExpectComplete(),
+	)
+
+	mux.Lock()
+	if subscriptions != 1 {
+		t.Errorf("Expected one subscription to the source, got %v", subscriptions)
+	}
+	mux.Unlock()
+}
+
+func TestPublishPacesToSlowestSubscriber(t *testing.T) {
+	publisher := flux.
+		Just(1, 2, 3).
+		Publish().
+		AutoConnect(2)
+
+	fastChecked := make(chan bool)
+
+	verifyConcurrently(
+		t,
+		verifier.
+			Create(publisher).
+			ThenRequest(3).
+			ThenAwait(time.Millisecond*50).
+			ExpectNextCount(1).
+			Then(func() {
+				close(fastChecked)
+			}).
+			ExpectNextCount(2).
+			ExpectComplete(),
+		verifier.
+			Create(publisher).
+			ThenRequest(1).
+			ExpectNextCount(1).
+			Then(func() {
+				<-fastChecked
+			}).
+			ThenRequest(2).
+			ExpectNextCount(2).
+			ExpectComplete(),
+	)
+}
+
+func TestPublishDispose(t *testing.T) {
+	publisher := flux.Never().Publish()
+
+	var connection cesium.Disposable
+
+	verifier.
+		Create(publisher).
+		Then(func() {
+			connection = publisher.Connect()
+		}).
+		Then(func() {
+			connection.Dispose()
+		}).
+		ExpectError(cesium.DisconnectedError).
+		Then(func() {
+			if !connection.IsDisposed() {
+				t.Errorf("Connection not disposed")
+			}
+		}).
+		Verify(t)
+}
+
+func TestPublishRefCount(t *testing.T) {
+	cancelled := make(chan bool, 1)
+
+	publisher := flux.
+		Range(0, 1000).
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		Publish().
+		RefCount(1, 0)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(0), int64(1)).
+		ThenCancel().
+		Then(func() {
+			select {
+			case <-cancelled:
+			case <-time.After(time.Millisecond * 100):
+				t.Errorf("Source not cancelled after the last subscriber left")
+			}
+		}).
+		Verify(t)
+}
+
+func TestPublishRefCountGracePeriod(t *testing.T) {
+	mux := sync.Mutex{}
+	subscriptions := 0
+
+	publisher := flux.
+		Range(0, 1000).
+		DoOnSubscribe(func(cesium.Subscription) {
+			mux.Lock()
+			subscriptions++
+			mux.Unlock()
+		}).
+		Publish().
+		RefCount(1, time.Millisecond*100)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(0), int64(1)).
+		ThenCancel().
+		Verify(t)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(2), int64(3)).
+		ThenCancel().
+		Verify(t)
+
+	mux.Lock()
+	if subscriptions != 1 {
+		t.Errorf("Expected one subscription to the source, got %v", subscriptions)
+	}
+	mux.Unlock()
+}
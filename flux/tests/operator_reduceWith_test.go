@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestReduceWith(t *testing.T) {
+	publisher := flux.
+		Just("a", "bb", "ccc").
+		ReduceWith(
+			func() cesium.T {
+				return 0
+			},
+			func(length cesium.T, t cesium.T) cesium.T {
+				return length.(int) + len(t.(string))
+			},
+		)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(6).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestReduceWithEmpty(t *testing.T) {
+	publisher := flux.
+		Empty().
+		ReduceWith(
+			func() cesium.T {
+				return 10
+			},
+			func(sum cesium.T, t cesium.T) cesium.T {
+				return sum.(int) + t.(int)
+			},
+		)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(10).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestReduceWithSeedPerSubscription(t *testing.T) {
+	seeds := 0
+
+	publisher := flux.
+		Just(1, 2).
+		ReduceWith(
+			func() cesium.T {
+				seeds++
+				return []cesium.T{}
+			},
+			func(slice cesium.T, t cesium.T) cesium.T {
+				return append(slice.([]cesium.T), t)
+			},
+		).
+		Map(func(t cesium.T) cesium.T {
+			return len(t.([]cesium.T))
+		})
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(publisher).
+			ExpectNext(2).
+			ExpectComplete().
+			Verify(t)
+	}
+
+	if seeds != 2 {
+		t.Errorf("Expected the seed supplier to be called once per subscription, got %v calls", seeds)
+	}
+}
+
+func TestReduceWithSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements != 1 {
+			return nil
+		}
+
+		return flux.
+			Just("a", "bb").
+			ReduceWith(
+				func() cesium.T {
+					return 0
+				},
+				func(length cesium.T, t cesium.T) cesium.T {
+					return length.(int) + len(t.(string))
+				},
+			)
+	})
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestReplay(t *testing.T) {
+	publisher := flux.
+		Just(1, 2, 3).
+		Replay(2)
+
+	publisher.Connect()
+	time.Sleep(time.Millisecond * 20)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(2, 3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestReplayIndependentBackpressure(t *testing.T) {
+	publisher := flux.
+		Just(1, 2, 3).
+		Replay(3)
+
+	publisher.Connect()
+
+	verifyConcurrently(
+		t,
+		verifier.
+			Create(publisher).
+			ThenRequest(1).
+			ThenAwait(time.Millisecond*50).
+			ExpectNextCount(1).
+			ThenRequest(2).
+			ExpectNextCount(2).
+			ExpectComplete(),
+		verifier.
+			Create(publisher).
+			ThenRequest(3).
+			ExpectNextCount(3).
+			ExpectComplete(),
+	)
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestScanWith(t *testing.T) {
+	publisher := flux.
+		Just("a", "bb", "ccc").
+		ScanWith(
+			func() cesium.T {
+				return 0
+			},
+			func(length cesium.T, t cesium.T) cesium.T {
+				return length.(int) + len(t.(string))
+			},
+		)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(0, 1, 3, 6).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestScanWithBackpressure(t *testing.T) {
+	publisher := flux.
+		Range(1, 100).
+		ScanWith(
+			func() cesium.T {
+				return int64(0)
+			},
+			func(sum cesium.T, t cesium.T) cesium.T {
+				return sum.(int64) + t.(int64)
+			},
+		)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(3).
+		ExpectNextCount(3).
+		ThenAwait(time.Millisecond * 20).
+		ExpectNext(int64(6)).
+		ThenCancel().
+		Verify(t)
+}
+
+func TestScanWithSeedPerSubscription(t *testing.T) {
+	seeds := 0
+
+	publisher := flux.
+		Just(1, 2).
+		ScanWith(
+			func() cesium.T {
+				seeds++
+				return 0
+			},
+			func(sum cesium.T, t cesium.T) cesium.T {
+				return sum.(int) + t.(int)
+			},
+		)
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(publisher).
+			ExpectNext(0, 1, 3).
+			ExpectComplete().
+			Verify(t)
+	}
+
+	if seeds != 2 {
+		t.Errorf("Expected the seed supplier to be called once per subscription, got %v calls", seeds)
+	}
+}
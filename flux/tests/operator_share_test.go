@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+type noopSubscriber struct{}
+
+func (s *noopSubscriber) OnNext(cesium.T)                 {}
+func (s *noopSubscriber) OnError(error)                   {}
+func (s *noopSubscriber) OnComplete()                     {}
+func (s *noopSubscriber) OnSubscribe(cesium.Subscription) {}
+
+func TestShare(t *testing.T) {
+	c := make(chan cesium.T)
+	publisher := flux.FromChannel(c).Share()
+
+	// The items are sent once both subscribers are subscribed, so that neither
+	// of them misses any.
+	subscribed := sync.WaitGroup{}
+	subscribed.Add(2)
+	go func() {
+		subscribed.Wait()
+		c <- 1
+		c <- 2
+		close(c)
+	}()
+
+	onSubscribe := func(cesium.Subscription) {
+		subscribed.Done()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		verifyConcurrently(
+			t,
+			verifier.Create(publisher.DoOnSubscribe(onSubscribe)).ExpectNext(1, 2).ExpectComplete(),
+			verifier.Create(publisher.DoOnSubscribe(onSubscribe)).ExpectNext(1, 2).ExpectComplete(),
+		)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("The shared items were not received by both subscribers within 5s")
+	}
+}
+
+func TestShareCancelsSourceWhenLastSubscriberLeaves(t *testing.T) {
+	cancelled := make(chan bool, 1)
+
+	publisher := flux.
+		Never().
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		Share()
+
+	first := publisher.Subscribe(&noopSubscriber{})
+	second := publisher.Subscribe(&noopSubscriber{})
+
+	first.Cancel()
+	select {
+	case <-cancelled:
+		t.Errorf("Source cancelled while a subscriber is still subscribed")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	second.Cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Millisecond * 100):
+		t.Errorf("Source not cancelled after the last subscriber left")
+	}
+}
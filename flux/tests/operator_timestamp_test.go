@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestTimestamp(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	start := scheduler.Now()
+
+	f := flux.Just(1, 2, 3).
+		DelayElements(time.Second, scheduler).
+		Timestamp(scheduler)
+
+	timestamped := func(item int, at time.Time) func(cesium.T) bool {
+		return func(t cesium.T) bool {
+			ts := t.(cesium.TimestampedItem)
+			return ts.Item == item && ts.Timestamp.Equal(at)
+		}
+	}
+
+	verifier.
+		Create(f).
+		ThenRequest(3).
+		ThenAwait(time.Millisecond * 10).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second * 3)
+		}).
+		ExpectNextMatches(timestamped(1, start.Add(time.Second))).
+		ExpectNextMatches(timestamped(2, start.Add(time.Second*2))).
+		ExpectNextMatches(timestamped(3, start.Add(time.Second*3))).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestTimestampScalarFlux(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	f := flux.Just(1).
+		Timestamp(scheduler).
+		Map(func(t cesium.T) cesium.T {
+			return t
+		})
+
+	// The timestamp must be taken upon emission, not upon assembly, even if
+	// followed by operators fused at assembly.
+	scheduler.AdvanceTimeBy(time.Hour)
+
+	verifier.
+		Create(f).
+		ExpectNextMatches(func(t cesium.T) bool {
+			ts := t.(cesium.TimestampedItem)
+			return ts.Item == 1 && ts.Timestamp.Equal(scheduler.Now())
+		}).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func isPanicWith(value interface{}) func(error) bool {
+	return func(err error) bool {
+		var panicErr *cesium.PanicError
+		return errors.As(err, &panicErr) && panicErr.Value == value && len(panicErr.Stack) > 0
+	}
+}
+
+func TestMapPanic(t *testing.T) {
+	cancelled := make(chan bool, 1)
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		Map(func(a cesium.T) cesium.T {
+			if a.(int) == 2 {
+				panic("boom")
+			}
+
+			return a
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Then(func() {
+			select {
+			case <-cancelled:
+			case <-time.After(time.Millisecond * 100):
+				t.Errorf("Upstream not cancelled")
+			}
+		}).
+		Verify(t)
+}
+
+func TestMapPanicScalar(t *testing.T) {
+	publisher := flux.
+		Just(1).
+		Map(func(a cesium.T) cesium.T {
+			panic("boom")
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestPanicWithErrorUnwraps(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		DoOnNext(func(a cesium.T) {
+			panic(err)
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(func(e error) bool {
+			return errors.Is(e, err)
+		}).
+		Verify(t)
+}
+
+func TestFilterPanic(t *testing.T) {
+	publisher := flux.
+		Range(1, 5).
+		Filter(func(a cesium.T) bool {
+			if a.(int64) == 3 {
+				panic("boom")
+			}
+
+			return true
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(int64(1), int64(2)).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestReducePanic(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		Reduce(func(a cesium.T, b cesium.T) cesium.T {
+			panic("boom")
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestHandlePanic(t *testing.T) {
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		Handle(func(a cesium.T, sink cesium.SynchronousSink) {
+			if a.(int) == 1 {
+				panic("boom")
+			}
+
+			sink.Next(a)
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(3).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestGeneratePanic(t *testing.T) {
+	i := 0
+	publisher := flux.Generate(func(sink cesium.SynchronousSink) {
+		i++
+		if i == 2 {
+			panic("boom")
+		}
+
+		sink.Next(i)
+	})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestCreatePanic(t *testing.T) {
+	publisher := flux.Create(func(sink cesium.FluxSink) {
+		panic("boom")
+	}, flux.OverflowStrategyBuffer)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestDeferPanic(t *testing.T) {
+	publisher := flux.Defer(func() cesium.Publisher {
+		panic("boom")
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestUsingPanic(t *testing.T) {
+	cleaned := make(chan cesium.T, 1)
+
+	publisher := flux.Using(
+		func() cesium.T {
+			return 1
+		},
+		func(resource cesium.T) cesium.Publisher {
+			panic("boom")
+		},
+		func(resource cesium.T) {
+			cleaned <- resource
+		},
+	)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Then(func() {
+			select {
+			case <-cleaned:
+			case <-time.After(time.Millisecond * 100):
+				t.Errorf("Resource not cleaned up")
+			}
+		}).
+		Verify(t)
+}
+
+func TestDoOnRequestPanic(t *testing.T) {
+	cancelled := make(chan bool, 1)
+
+	publisher := flux.
+		Just(1, 2, 3).
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		DoOnRequest(func(int64) {
+			panic("boom")
+		})
+
+	_, err := publisher.ToSlice()
+	if !isPanicWith("boom")(err) {
+		t.Errorf("Expected a panic error, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Millisecond * 100):
+		t.Errorf("Upstream not cancelled")
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+type subscriptionSubscriber struct {
+	noopSubscriber
+	subscription cesium.Subscription
+}
+
+func (s *subscriptionSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	s.subscription = subscription
+}
+
+func TestRequestZero(t *testing.T) {
+	cancelled := int32(0)
+
+	f := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		DoOnCancel(func() {
+			atomic.AddInt32(&cancelled, 1)
+		})
+
+	verifier.
+		Create(f).
+		ThenRequest(0).
+		ExpectError(cesium.NonPositiveRequestError).
+		Verify(t)
+
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Errorf("Expected the source to be cancelled once, was cancelled %v times", cancelled)
+	}
+}
+
+func TestRequestNegativeAfterItems(t *testing.T) {
+	f := flux.FromSlice([]cesium.T{1, 2, 3})
+
+	verifier.
+		Create(f).
+		ExpectNext(1).
+		ThenRequest(-1).
+		ExpectError(cesium.NonPositiveRequestError).
+		Verify(t)
+}
+
+func TestNilItem(t *testing.T) {
+	cancelled := int32(0)
+
+	f := flux.
+		FromSlice([]cesium.T{1, nil, 2}).
+		DoOnCancel(func() {
+			atomic.AddInt32(&cancelled, 1)
+		})
+
+	verifier.
+		Create(f).
+		ThenRequest(3).
+		ExpectNext(1).
+		ExpectError(cesium.NilItemError).
+		Verify(t)
+
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Errorf("Expected the source to be cancelled once, was cancelled %v times", cancelled)
+	}
+}
+
+func TestRequestCappedAtMaxInt64(t *testing.T) {
+	f := flux.Range(0, 3)
+
+	verifier.
+		Create(f).
+		ThenRequest(math.MaxInt64).
+		ThenRequest(math.MaxInt64).
+		ExpectNext(int64(0), int64(1), int64(2)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestCancelIsIdempotent(t *testing.T) {
+	cancelled := int32(0)
+
+	f := flux.
+		Never().
+		DoOnCancel(func() {
+			atomic.AddInt32(&cancelled, 1)
+		})
+
+	subscriber := &subscriptionSubscriber{}
+	subscription := f.Subscribe(subscriber)
+	subscription.Cancel()
+	subscription.Cancel()
+	subscriber.subscription.Cancel()
+
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Errorf("Expected the source to be cancelled once, was cancelled %v times", cancelled)
+	}
+}
@@ -0,0 +1,82 @@
+// Package hooks provides the global callbacks and switches affecting all the
+// pipelines, meant for debugging, logging and monitoring.
+//
+// The dropped signal hooks are called with the signals that are lost, like the
+// items emitted by a flux.Create sink without a demand or the signals an
+// operator receives after it has terminated.
+package hooks
+
+import (
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/internal"
+)
+
+// OnNextDropped sets the function called with each dropped item, replacing the
+// previous one.
+func OnNextDropped(f func(cesium.T)) {
+	internal.SetOnNextDropped(f)
+}
+
+// ResetOnNextDropped removes the function set by OnNextDropped.
+func ResetOnNextDropped() {
+	internal.SetOnNextDropped(nil)
+}
+
+// OnErrorDropped sets the function called with each dropped error, replacing
+// the previous one.
+func OnErrorDropped(f func(error)) {
+	internal.SetOnErrorDropped(f)
+}
+
+// ResetOnErrorDropped removes the function set by OnErrorDropped.
+func ResetOnErrorDropped() {
+	internal.SetOnErrorDropped(nil)
+}
+
+// OnOperatorError sets the function mapping the errors raised by the user
+// supplied functions of the operators (like a panic in the mapper of
+// Flux.Map or an error returned from the mapper of Flux.MapE) before they are
+// emitted. It receives the item being processed, or nil if there is none. If
+// it returns nil, the original error is emitted.
+func OnOperatorError(f func(error, cesium.T) error) {
+	internal.SetOnOperatorError(f)
+}
+
+// ResetOnOperatorError removes the function set by OnOperatorError.
+func ResetOnOperatorError() {
+	internal.SetOnOperatorError(nil)
+}
+
+// OnEachOperator sets the function under the key that decorates each operator
+// assembled from now on, replacing the one previously set under the same key.
+// The operators a decorator assembles from the operator it receives are not
+// decorated.
+func OnEachOperator(key string, f func(cesium.Publisher) cesium.Publisher) {
+	internal.SetOnEachOperator(key, f)
+}
+
+// ResetOnEachOperator removes the decorator set under the key by
+// OnEachOperator.
+func ResetOnEachOperator(key string) {
+	internal.SetOnEachOperator(key, nil)
+}
+
+// OnOperatorDebug makes each operator assembled from now on capture the call
+// site it was assembled at. The errors passing through the operators are
+// wrapped in a cesium.AssemblyError listing them. Capturing the call sites is
+// expensive, so this is meant for debugging only.
+func OnOperatorDebug() {
+	internal.EnableOperatorDebug()
+}
+
+// ResetOnOperatorDebug stops the operators assembled from now on from
+// capturing their call sites.
+func ResetOnOperatorDebug() {
+	internal.DisableOperatorDebug()
+}
+
+// ResetAll removes all the hooks and disables the operator debug mode.
+func ResetAll() {
+	internal.ResetHooks()
+	internal.DisableOperatorDebug()
+}
@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/hooks"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+type recorder struct {
+	mux   sync.Mutex
+	items []interface{}
+}
+
+func (r *recorder) record(item interface{}) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.items = append(r.items, item)
+}
+
+func (r *recorder) recorded() []interface{} {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return append([]interface{}{}, r.items...)
+}
+
+func TestOnNextDropped(t *testing.T) {
+	defer hooks.ResetAll()
+
+	dropped := &recorder{}
+	hooks.OnNextDropped(func(item cesium.T) {
+		dropped.record(item)
+	})
+
+	publisher := flux.Create(func(sink cesium.FluxSink) {
+		sink.Next(1)
+		sink.Next(2)
+		sink.Complete()
+	}, flux.OverflowStrategyDrop)
+
+	verifier.
+		Create(publisher).
+		ThenAwait(time.Millisecond * 50).
+		ThenRequest(1).
+		ExpectComplete().
+		Then(func() {
+			if r := dropped.recorded(); !reflect.DeepEqual(r, []interface{}{1, 2}) {
+				t.Errorf("Expected dropped items [1 2], got %v", r)
+			}
+		}).
+		Verify(t)
+}
+
+func TestOnErrorDropped(t *testing.T) {
+	defer hooks.ResetAll()
+
+	first, second := errors.New("first"), errors.New("second")
+	dropped := &recorder{}
+	hooks.OnErrorDropped(func(err error) {
+		dropped.record(err)
+	})
+
+	publisher := mono.Create(func(sink cesium.MonoSink) {
+		sink.Error(first)
+		sink.Error(second)
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectError(first).
+		Then(func() {
+			if r := dropped.recorded(); !reflect.DeepEqual(r, []interface{}{second}) {
+				t.Errorf("Expected dropped errors [second], got %v", r)
+			}
+		}).
+		Verify(t)
+}
+
+func TestOnOperatorError(t *testing.T) {
+	defer hooks.ResetAll()
+
+	mapped := errors.New("mapped")
+	items := &recorder{}
+	hooks.OnOperatorError(func(err error, item cesium.T) error {
+		items.record(item)
+		return mapped
+	})
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2, 3}).
+		Map(func(a cesium.T) cesium.T {
+			if a.(int) == 2 {
+				panic("boom")
+			}
+
+			return a
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ThenRequest(1).
+		ExpectError(mapped).
+		Then(func() {
+			if r := items.recorded(); !reflect.DeepEqual(r, []interface{}{2}) {
+				t.Errorf("Expected failed items [2], got %v", r)
+			}
+		}).
+		Verify(t)
+}
+
+func TestOnEachOperator(t *testing.T) {
+	defer hooks.ResetAll()
+
+	seen := &recorder{}
+	hooks.OnEachOperator("seen", func(p cesium.Publisher) cesium.Publisher {
+		return p.(cesium.Flux).DoOnNext(func(item cesium.T) {
+			seen.record(item)
+		})
+	})
+
+	publisher := flux.
+		FromSlice([]cesium.T{1, 2}).
+		Map(func(a cesium.T) cesium.T {
+			return 10 * a.(int)
+		})
+
+	hooks.ResetOnEachOperator("seen")
+
+	verifier.
+		Create(publisher).
+		ExpectNext(10, 20).
+		ExpectComplete().
+		Then(func() {
+			if r := seen.recorded(); !reflect.DeepEqual(r, []interface{}{1, 10, 2, 20}) {
+				t.Errorf("Expected decorated operators to see [1 10 2 20], got %v", r)
+			}
+		}).
+		Verify(t)
+}
+
+func TestOnEachOperatorDecoratesConcurrentAssemblies(t *testing.T) {
+	defer hooks.ResetAll()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	calls := &recorder{}
+	hooks.OnEachOperator("blocking", func(p cesium.Publisher) cesium.Publisher {
+		calls.record(p)
+		if len(calls.recorded()) == 1 {
+			close(entered)
+			<-release
+		}
+
+		return p
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		flux.Never()
+	}()
+
+	// The operator is assembled while the decorator of the other goroutine
+	// runs.
+	<-entered
+	flux.Never()
+	close(release)
+	<-done
+
+	if c := len(calls.recorded()); c != 2 {
+		t.Errorf("Expected both operators to be decorated, got %v decorations", c)
+	}
+}
+
+func TestOnEachOperatorDecoratesEveryOperator(t *testing.T) {
+	defer hooks.ResetAll()
+
+	source := flux.Just(1, 2)
+	connectable := source.Publish()
+	parallel := source.Parallel(2)
+	monoSource := mono.Just(1)
+
+	calls := &recorder{}
+	hooks.OnEachOperator("counting", func(p cesium.Publisher) cesium.Publisher {
+		calls.record(p)
+		return p
+	})
+
+	operators := map[string]func(){
+		"LimitRate":       func() { source.LimitRate(2, 1) },
+		"OnErrorContinue": func() { source.OnErrorContinue(func(error, cesium.T) {}) },
+		"RefCount":        func() { connectable.RefCount(1, 0) },
+		"AutoConnect":     func() { connectable.AutoConnect(1) },
+		"Sequential":      func() { parallel.Sequential() },
+		"Mono.Share":      func() { monoSource.Share() },
+	}
+
+	for name, assemble := range operators {
+		before := len(calls.recorded())
+		assemble()
+		if len(calls.recorded()) == before {
+			t.Errorf("Expected %v to be decorated", name)
+		}
+	}
+}
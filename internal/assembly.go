@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// libraryPath is the import path of the library, used to tell its frames
+// apart from the ones of the code assembling the pipeline.
+var libraryPath = strings.TrimSuffix(reflect.TypeOf(Flux{}).PkgPath(), "/internal")
+
+var operatorDebug int32
+
+// EnableOperatorDebug makes the operators capture the call site they were
+// assembled at, so that the errors passing through them carry the trace.
+func EnableOperatorDebug() {
+	atomic.StoreInt32(&operatorDebug, 1)
+}
+
+// DisableOperatorDebug stops the operators from capturing their call sites.
+func DisableOperatorDebug() {
+	atomic.StoreInt32(&operatorDebug, 0)
+}
+
+func isOperatorDebug() bool {
+	return atomic.LoadInt32(&operatorDebug) != 0
+}
+
+// isLibraryFunction reports whether the fully qualified function name belongs
+// to one of the library packages. The test packages are treated as user code.
+func isLibraryFunction(function string) bool {
+	pkg := function
+	if slash := strings.LastIndex(pkg, "/"); slash >= 0 {
+		if dot := strings.Index(pkg[slash:], "."); dot >= 0 {
+			pkg = pkg[:slash+dot]
+		}
+	}
+
+	if pkg != libraryPath && !strings.HasPrefix(pkg, libraryPath+"/") {
+		return false
+	}
+
+	return !strings.HasSuffix(pkg, "_test") && !strings.HasSuffix(pkg, "/tests")
+}
+
+// operatorName turns a library function name like
+// "github.com/DusanKasan/cesium/internal.(*Flux).Map" into "Flux.Map".
+func operatorName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	if strings.HasPrefix(name, "internal.") {
+		name = name[len("internal."):]
+	}
+
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// assemblySite describes the operator being assembled and the call site in
+// the user code it was assembled at. It returns false if the pipeline is not
+// assembled from the user code.
+func assemblySite() (string, bool) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	operator := ""
+	for {
+		frame, more := frames.Next()
+		if !isLibraryFunction(frame.Function) {
+			if operator == "" {
+				return "", false
+			}
+
+			return fmt.Sprintf("%s at %s (%s:%d)", operator, frame.Function, frame.File, frame.Line), true
+		}
+
+		operator = operatorName(frame.Function)
+		if !more {
+			return "", false
+		}
+	}
+}
+
+// traceError adds the entry to the assembly trace of the error.
+func traceError(err error, entry string) error {
+	var assemblyErr *cesium.AssemblyError
+	if errors.As(err, &assemblyErr) && assemblyErr == err {
+		if trace := assemblyErr.Trace; len(trace) > 0 && trace[len(trace)-1] == entry {
+			return err
+		}
+
+		trace := make([]string, len(assemblyErr.Trace), len(assemblyErr.Trace)+1)
+		copy(trace, assemblyErr.Trace)
+
+		return &cesium.AssemblyError{Err: assemblyErr.Err, Trace: append(trace, entry)}
+	}
+
+	return &cesium.AssemblyError{Err: err, Trace: []string{entry}}
+}
+
+// tracingSubscriber adds the entry to the assembly trace of the errors passing
+// through it.
+type tracingSubscriber struct {
+	cesium.Subscriber
+	entry string
+}
+
+func (s *tracingSubscriber) OnError(err error) {
+	s.Subscriber.OnError(traceError(err, s.entry))
+}
+
+func (s *tracingSubscriber) errorContinue() func(error, cesium.T) {
+	return errorContinueOf(s.Subscriber)
+}
+
+type conditionalTracingSubscriber struct {
+	*tracingSubscriber
+	conditional ConditionalSubscriber
+}
+
+func (s *conditionalTracingSubscriber) OnNextIf(t cesium.T) bool {
+	return s.conditional.OnNextIf(t)
+}
+
+func newTracingSubscriber(s cesium.Subscriber, entry string) cesium.Subscriber {
+	t := &tracingSubscriber{s, entry}
+	if c, ok := s.(ConditionalSubscriber); ok {
+		return &conditionalTracingSubscriber{t, c}
+	}
+
+	return t
+}
+
+func traced(onSubscribe func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription, entry string) func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription {
+	return func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		return onSubscribe(newTracingSubscriber(subscriber, entry), scheduler)
+	}
+}
+
+// onFluxAssembly is called with each assembled operator and the publisher it
+// was assembled from, nil for the factories. It applies the decorators set up
+// through SetOnEachOperator and in the operator debug mode the operator
+// records its call site into the errors passing through it.
+//
+// The decorators receive the operator marked as undecorated. The operators
+// assembled from a publisher so marked inherit the mark and are not
+// decorated, so that a decorator does not decorate its own operators
+// endlessly. An operator not assembled from a publisher can be marked by the
+// caller.
+func onFluxAssembly(source cesium.Publisher, f *Flux) *Flux {
+	if f.undecorated || isUndecorated(source) {
+		f.undecorated = true
+	} else if decorators := operatorDecorators(); decorators != nil {
+		view := &Flux{OnSubscribe: f.OnSubscribe, undecorated: true}
+		f = &Flux{OnSubscribe: subscribeFunc(decorate(decorators, view))}
+	}
+
+	if !isOperatorDebug() {
+		return f
+	}
+
+	site, ok := assemblySite()
+	if !ok {
+		return f
+	}
+
+	return &Flux{OnSubscribe: traced(f.OnSubscribe, site), undecorated: f.undecorated}
+}
+
+// onMonoAssembly is the cesium.Mono counterpart of onFluxAssembly.
+func onMonoAssembly(source cesium.Publisher, m *Mono) *Mono {
+	if m.undecorated || isUndecorated(source) {
+		m.undecorated = true
+	} else if decorators := operatorDecorators(); decorators != nil {
+		view := &Mono{OnSubscribe: m.OnSubscribe, undecorated: true}
+		m = &Mono{OnSubscribe: subscribeFunc(decorate(decorators, view))}
+	}
+
+	if !isOperatorDebug() {
+		return m
+	}
+
+	site, ok := assemblySite()
+	if !ok {
+		return m
+	}
+
+	return &Mono{OnSubscribe: traced(m.OnSubscribe, site), undecorated: m.undecorated}
+}
+
+// isUndecorated reports whether the publisher was assembled by a decorator from
+// the operator it received, see onFluxAssembly.
+func isUndecorated(publisher cesium.Publisher) bool {
+	switch p := publisher.(type) {
+	case *Flux:
+		return p.undecorated
+	case *Mono:
+		return p.undecorated
+	case *ScalarFlux:
+		return isUndecorated(p.Flux)
+	case *FuseableFlux:
+		return p.Flux.undecorated
+	case *ConnectableFlux:
+		return p.Flux.undecorated
+	case *ScalarMono:
+		return isUndecorated(p.Mono)
+	default:
+		return false
+	}
+}
+
+// checkpointEntry describes the checkpoint in the assembly trace, along with
+// its call site in the operator debug mode.
+func checkpointEntry(description string) string {
+	entry := fmt.Sprintf("checkpoint(%q)", description)
+	if !isOperatorDebug() {
+		return entry
+	}
+
+	if site, ok := assemblySite(); ok {
+		return entry + " " + site[strings.Index(site, "at "):]
+	}
+
+	return entry
+}
+
+// Checkpoint adds the description to the assembly trace of the errors passing
+// through it.
+func (f *Flux) Checkpoint(description string) cesium.Flux {
+	return &Flux{OnSubscribe: traced(f.OnSubscribe, checkpointEntry(description))}
+}
+
+// Checkpoint adds the description to the assembly trace of the errors passing
+// through it.
+func (m *Mono) Checkpoint(description string) cesium.Mono {
+	return &Mono{OnSubscribe: traced(m.OnSubscribe, checkpointEntry(description))}
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// channelSource emits the items received from a channel to one subscriber at
+// a time. While there is no demand, it waits on a signal channel for a
+// request, and the receive from the channel is raced against the
+// cancellation, so no CPU is used while idle. Once the subscriber cancels or
+// the emission terminates, the source can be subscribed to again and the next
+// subscriber continues with the following items, after the emission to the
+// previous one has stopped.
+type channelSource struct {
+	ch <-chan cesium.T
+
+	// limit is the number of items to emit before completing, zero meaning
+	// all of them.
+	limit int64
+
+	mux     sync.Mutex
+	current *channelSubscription
+
+	// emitting is held while emitting to a subscriber.
+	emitting sync.Mutex
+
+	// pending is the item received for a subscriber that cancelled
+	// meanwhile, kept for the next one.
+	pending    cesium.T
+	hasPending bool
+}
+
+// channelSubscription is the subscription to a channelSource.
+type channelSubscription struct {
+	requested int64 // first, to be aligned for the atomic operations
+	cancelled int32
+	source    *channelSource
+	demand    chan struct{}
+	done      chan struct{}
+}
+
+func (s *channelSubscription) request(n int64) {
+	if addDemandAtomic(&s.requested, n) != 0 {
+		return
+	}
+
+	select {
+	case s.demand <- struct{}{}:
+	default:
+	}
+}
+
+func (s *channelSubscription) cancel() {
+	if atomic.CompareAndSwapInt32(&s.cancelled, 0, 1) {
+		close(s.done)
+		s.source.release(s)
+	}
+}
+
+func (s *channelSubscription) isCancelled() bool {
+	return atomic.LoadInt32(&s.cancelled) != 0
+}
+
+func (c *channelSource) subscribe(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+	s := &channelSubscription{
+		source: c,
+		demand: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	sub := &Subscription{
+		CancelFunc:  s.cancel,
+		RequestFunc: s.request,
+	}
+
+	c.mux.Lock()
+	if c.current != nil {
+		c.mux.Unlock()
+
+		subscriber.OnSubscribe(sub)
+		subscriber.OnError(cesium.SingleSubscriberError)
+		return sub
+	}
+	c.current = s
+	c.mux.Unlock()
+
+	if scheduler == nil {
+		scheduler = SeparateGoroutineScheduler()
+	}
+
+	subscriber.OnSubscribe(sub)
+	scheduler.Schedule(func(cesium.Canceller) {
+		c.emitting.Lock()
+		defer c.emitting.Unlock()
+		defer c.release(s)
+
+		c.emit(subscriber, s)
+	})
+
+	return sub
+}
+
+// emit emits the items as they are requested until the channel is closed, the
+// limit is reached or the subscription is cancelled. The source is released
+// before completing, so that it can be subscribed to again from OnComplete.
+func (c *channelSource) emit(subscriber cesium.Subscriber, s *channelSubscription) {
+	for emitted := int64(0); c.limit == 0 || emitted < c.limit; emitted++ {
+		for atomic.LoadInt64(&s.requested) == 0 {
+			select {
+			case <-s.demand:
+			case <-s.done:
+				return
+			}
+		}
+
+		t, ok := c.takePending()
+		if !ok {
+			select {
+			case t, ok = <-c.ch:
+				if !ok {
+					c.release(s)
+					subscriber.OnComplete()
+					return
+				}
+			case <-s.done:
+				return
+			}
+		}
+
+		if s.isCancelled() {
+			c.putPending(t)
+			return
+		}
+
+		produceDemand(&s.requested, 1)
+		subscriber.OnNext(t)
+	}
+
+	if !s.isCancelled() {
+		c.release(s)
+		subscriber.OnComplete()
+	}
+}
+
+func (c *channelSource) takePending() (cesium.T, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	t, ok := c.pending, c.hasPending
+	c.pending, c.hasPending = nil, false
+
+	return t, ok
+}
+
+func (c *channelSource) putPending(t cesium.T) {
+	c.mux.Lock()
+	c.pending, c.hasPending = t, true
+	c.mux.Unlock()
+}
+
+// release allows subscribing again, unless another subscription has been
+// made already.
+func (c *channelSource) release(s *channelSubscription) {
+	c.mux.Lock()
+	if c.current == s {
+		c.current = nil
+	}
+	c.mux.Unlock()
+}
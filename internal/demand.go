@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// The demand is tracked as the number of items requested and not yet emitted,
+// math.MaxInt64 standing for an unbounded demand which is never decremented.
+
+// addDemand adds n to the requested amount, capping it at math.MaxInt64.
+// Non-positive amounts are ignored, as they are rejected by the subscriptions
+// (see specSubscription).
+func addDemand(requested int64, n int64) int64 {
+	if n <= 0 {
+		return requested
+	}
+
+	if requested == math.MaxInt64 || n >= math.MaxInt64-requested {
+		return math.MaxInt64
+	}
+
+	return requested + n
+}
+
+// addDemandAtomic atomically adds n to the demand, capping it at
+// math.MaxInt64. It returns the demand before the addition, a zero meaning
+// that the caller is the one to resume the emission.
+func addDemandAtomic(requested *int64, n int64) int64 {
+	for {
+		r := atomic.LoadInt64(requested)
+		if r == math.MaxInt64 || n <= 0 {
+			return r
+		}
+
+		if atomic.CompareAndSwapInt64(requested, r, addDemand(r, n)) {
+			return r
+		}
+	}
+}
+
+// produceDemand atomically subtracts the n emitted items from the demand,
+// unless it is unbounded. It returns the remaining demand, never going below
+// zero.
+func produceDemand(requested *int64, n int64) int64 {
+	for {
+		r := atomic.LoadInt64(requested)
+		if r == math.MaxInt64 {
+			return r
+		}
+
+		u := r - n
+		if u < 0 {
+			u = 0
+		}
+
+		if atomic.CompareAndSwapInt64(requested, r, u) {
+			return u
+		}
+	}
+}
+
+// takeDemand atomically takes the whole demand, leaving zero behind unless it
+// is unbounded. It is used to pass the accumulated demand upstream.
+func takeDemand(requested *int64) int64 {
+	for {
+		r := atomic.LoadInt64(requested)
+		if r == math.MaxInt64 || atomic.CompareAndSwapInt64(requested, r, 0) {
+			return r
+		}
+	}
+}
+
+// addDemandLimited atomically adds up to n to the demand, never exceeding the
+// limit in total. It returns the amount actually added.
+func addDemandLimited(requested *int64, n int64, limit int64) int64 {
+	for {
+		r := atomic.LoadInt64(requested)
+		added := n
+		if added > limit-r {
+			added = limit - r
+		}
+
+		if added <= 0 {
+			return 0
+		}
+
+		if atomic.CompareAndSwapInt64(requested, r, r+added) {
+			return added
+		}
+	}
+}
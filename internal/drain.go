@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// drainLoop emits the signals of a synchronous source on the scheduler,
+// serving the demand iteratively. A request only adds to the demand and
+// increments the work-in-progress counter, the drain is scheduled when the
+// counter leaves zero. The requests made while draining, like the ones a
+// filter makes from OnNext when it rejects an item, are served by the loop
+// already running instead of recursing into it.
+type drainLoop struct {
+	requested int64 // first, to be aligned for the atomic operations
+	scheduler cesium.Scheduler
+
+	// emit emits the next item of the source, or its terminal signal. It
+	// reports whether the item was accepted, so that it counts against the
+	// demand, and whether the source has terminated. The sources emitting
+	// their last item should terminate within the same call, as no more
+	// demand may come.
+	emit func() (accepted bool, terminated bool)
+
+	wip       int32
+	cancelled int32
+	done      bool
+}
+
+// newDrainLoop returns a drainLoop emitting on the scheduler, or on a
+// separate goroutine if there is none.
+func newDrainLoop(scheduler cesium.Scheduler) *drainLoop {
+	if scheduler == nil {
+		scheduler = SeparateGoroutineScheduler()
+	}
+
+	return &drainLoop{scheduler: scheduler}
+}
+
+// subscription returns the subscription requesting from and cancelling the
+// loop.
+func (d *drainLoop) subscription() *Subscription {
+	return &Subscription{
+		CancelFunc:  d.cancel,
+		RequestFunc: d.request,
+	}
+}
+
+func (d *drainLoop) request(n int64) {
+	addDemandAtomic(&d.requested, n)
+
+	if atomic.AddInt32(&d.wip, 1) == 1 {
+		d.scheduler.Schedule(func(cesium.Canceller) {
+			d.drain()
+		})
+	}
+}
+
+func (d *drainLoop) cancel() {
+	atomic.StoreInt32(&d.cancelled, 1)
+}
+
+func (d *drainLoop) isCancelled() bool {
+	return atomic.LoadInt32(&d.cancelled) != 0
+}
+
+// drain emits while there is demand, then gives up the work-in-progress
+// counter, looping again if it was incremented meanwhile.
+func (d *drainLoop) drain() {
+	missed := int32(1)
+
+	for {
+		for !d.done && !d.isCancelled() && atomic.LoadInt64(&d.requested) > 0 {
+			accepted, terminated := d.emit()
+			if terminated {
+				d.done = true
+			} else if accepted {
+				produceDemand(&d.requested, 1)
+			}
+		}
+
+		missed = atomic.AddInt32(&d.wip, -missed)
+		if missed == 0 {
+			return
+		}
+	}
+}
+
+// onNextOf returns the function emitting an item to the subscriber, through
+// OnNextIf if it is a ConditionalSubscriber, reporting whether the item was
+// accepted.
+func onNextOf(subscriber cesium.Subscriber) func(cesium.T) bool {
+	if s, ok := subscriber.(ConditionalSubscriber); ok {
+		return s.OnNextIf
+	}
+
+	return func(t cesium.T) bool {
+		subscriber.OnNext(t)
+		return true
+	}
+}
@@ -3,6 +3,8 @@ package internal
 import (
 	"log"
 
+	"sort"
+
 	"sync"
 
 	"time"
@@ -12,10 +14,14 @@ import (
 
 type Flux struct {
 	OnSubscribe func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription
+
+	// undecorated is set on the operators passed to the decorators and the
+	// ones assembled from them, see onFluxAssembly.
+	undecorated bool
 }
 
 func (f *Flux) Subscribe(subscriber cesium.Subscriber) cesium.Subscription {
-	return f.OnSubscribe(subscriber, nil)
+	return subscribeSpec(f.OnSubscribe, subscriber)
 }
 
 type ConditionalSubscriber interface {
@@ -31,6 +37,28 @@ func (f *Flux) Map(mapper func(t cesium.T) cesium.T) cesium.Flux {
 	return FluxMapOperator(f, mapper)
 }
 
+func (f *Flux) MapE(mapper func(t cesium.T) (cesium.T, error)) cesium.Flux {
+	return FluxMapEOperator(f, mapper)
+}
+
+func (f *Flux) Timestamp(scheduler ...cesium.TimedScheduler) cesium.Flux {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return FluxTimestampOperator(f, sch)
+}
+
+func (f *Flux) Elapsed(scheduler ...cesium.TimedScheduler) cesium.Flux {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return FluxElapsedOperator(f, sch)
+}
+
 func (f *Flux) DoFinally(fn func()) cesium.Flux {
 	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
 		p := DoFinallyProcessor(fn)
@@ -52,7 +80,7 @@ func (f *Flux) DoFinally(fn func()) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Count() cesium.Mono {
@@ -80,7 +108,7 @@ func (f *Flux) Reduce(fn func(cesium.T, cesium.T) cesium.T) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(f, &Mono{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Scan(fn func(cesium.T, cesium.T) cesium.T) cesium.Flux {
@@ -104,7 +132,50 @@ func (f *Flux) Scan(fn func(cesium.T, cesium.T) cesium.T) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) ReduceWith(seedSupplier func() cesium.T, fn func(cesium.T, cesium.T) cesium.T) cesium.Mono {
+	type accumulator struct {
+		value cesium.T
+	}
+
+	return f.collect(
+		func() cesium.T {
+			return &accumulator{seedSupplier()}
+		},
+		func(container cesium.T, t cesium.T) {
+			a := container.(*accumulator)
+			a.value = fn(a.value, t)
+		},
+		func(container cesium.T) cesium.T {
+			return container.(*accumulator).value
+		},
+	)
+}
+
+func (f *Flux) ScanWith(seedSupplier func() cesium.T, fn func(cesium.T, cesium.T) cesium.T) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := ScanWithProcessor(seedSupplier(), fn)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := f.OnSubscribe(p, scheduler)
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) All(fn func(cesium.T) bool) cesium.Mono {
@@ -128,7 +199,7 @@ func (f *Flux) All(fn func(cesium.T) bool) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(f, &Mono{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Any(fn func(cesium.T) bool) cesium.Mono {
@@ -153,7 +224,7 @@ func (f *Flux) Any(fn func(cesium.T) bool) cesium.Mono {
 
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(f, &Mono{OnSubscribe: onPublish})
 }
 
 func (f *Flux) HasElements() cesium.Mono {
@@ -177,7 +248,7 @@ func (f *Flux) HasElements() cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(f, &Mono{OnSubscribe: onPublish})
 }
 
 func (f *Flux) HasElement(element cesium.T) cesium.Mono {
@@ -203,7 +274,7 @@ func (f *Flux) HasElement(element cesium.T) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(f, &Mono{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Log(logger *log.Logger) cesium.Flux {
@@ -245,7 +316,7 @@ func (f *Flux) Log(logger *log.Logger) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnNext(fn func(cesium.T)) cesium.Flux {
@@ -276,7 +347,7 @@ func (f *Flux) DoOnNext(fn func(cesium.T)) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnError(fn func(error)) cesium.Flux {
@@ -309,7 +380,7 @@ func (f *Flux) DoOnError(fn func(error)) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnCancel(fn func()) cesium.Flux {
@@ -342,7 +413,7 @@ func (f *Flux) DoOnCancel(fn func()) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnSubscribe(fn func(cesium.Subscription)) cesium.Flux {
@@ -375,7 +446,7 @@ func (f *Flux) DoOnSubscribe(fn func(cesium.Subscription)) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnRequest(fn func(int64)) cesium.Flux {
@@ -408,7 +479,7 @@ func (f *Flux) DoOnRequest(fn func(int64)) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnTerminate(fn func()) cesium.Flux {
@@ -443,7 +514,7 @@ func (f *Flux) DoOnTerminate(fn func()) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnComplete(fn func()) cesium.Flux {
@@ -476,7 +547,7 @@ func (f *Flux) DoOnComplete(fn func()) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoAfterTerminate(fn func()) cesium.Flux {
@@ -500,7 +571,7 @@ func (f *Flux) DoAfterTerminate(fn func()) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Flux {
@@ -524,7 +595,7 @@ func (f *Flux) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Concat(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.Flux {
@@ -548,7 +619,31 @@ func (f *Flux) Concat(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) ConcatDelayError(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := ConcatDelayErrorProcessor(publishers)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := f.OnSubscribe(p, scheduler)
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) ConcatWith(publishers ...cesium.Publisher) cesium.Flux {
@@ -577,7 +672,7 @@ func (f *Flux) ConcatWith(publishers ...cesium.Publisher) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DistinctUntilChanged() cesium.Flux {
@@ -601,7 +696,7 @@ func (f *Flux) DistinctUntilChanged() cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Take(n int64) cesium.Flux {
@@ -625,7 +720,34 @@ func (f *Flux) Take(n int64) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) LimitRequest(n int64) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := LimitRequestProcessor(n)
+
+		// The processor passes its subscription to the subscriber, which
+		// cancels upstream as well.
+		sub := p.Subscribe(subscriber)
+		f.OnSubscribe(p, scheduler)
+
+		return sub
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) LimitRate(highTide int64, lowTide int64) cesium.Flux {
+	if highTide <= 0 {
+		highTide = publishPrefetch
+	}
+
+	if lowTide <= 0 || lowTide > highTide {
+		lowTide = highTide
+	}
+
+	return publishOn(f, ImmediateScheduler(), highTide, lowTide)
 }
 
 func (f *Flux) FlatMap(fn func(cesium.T) cesium.Publisher, scheduler ...cesium.Scheduler) cesium.Flux {
@@ -654,7 +776,7 @@ func (f *Flux) FlatMap(fn func(cesium.T) cesium.Publisher, scheduler ...cesium.S
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) BlockFirst() (cesium.T, bool, error) {
@@ -830,7 +952,7 @@ func (f *Flux) OnErrorReturn(fallbackValue cesium.T) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) DoOnEach(fn func(cesium.Signal)) cesium.Flux {
@@ -853,7 +975,7 @@ func (f *Flux) DoOnEach(fn func(cesium.Signal)) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Materialize() cesium.Flux {
@@ -876,7 +998,7 @@ func (f *Flux) Materialize() cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) Dematerialize() cesium.Flux {
@@ -899,13 +1021,14 @@ func (f *Flux) Dematerialize() cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) ToSlice() ([]cesium.T, error) {
 	var buffer []cesium.T
 
-	c := make(chan error)
+	// Buffered, as the terminal signal may come from within the request below.
+	c := make(chan error, 1)
 	once := sync.Once{}
 
 	sub := f.Subscribe(DoObserver(
@@ -978,7 +1101,7 @@ func (f *Flux) OnErrorResume(predicate func(error) bool, publisher cesium.Publis
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
 }
 
 func (f *Flux) OnErrorMap(mapper func(error) error) cesium.Flux {
@@ -1001,5 +1124,234 @@ func (f *Flux) OnErrorMap(mapper func(error) error) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) FilterWhen(fn func(cesium.T) cesium.Publisher, bufferSize int) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := FilterWhenProcessor(fn, bufferSize)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := f.OnSubscribe(p, scheduler)
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) DelayElements(delay time.Duration, scheduler ...cesium.TimedScheduler) cesium.Flux {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	onPublish := func(subscriber cesium.Subscriber, s cesium.Scheduler) cesium.Subscription {
+		p := DelayElementsProcessor(delay, sch)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := f.OnSubscribe(p, s)
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+func (f *Flux) DelaySubscription(delay time.Duration, scheduler ...cesium.TimedScheduler) cesium.Flux {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return f.DelaySubscriptionUntil(monoDelay(delay, sch))
+}
+
+func (f *Flux) DelaySubscriptionUntil(trigger cesium.Publisher) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		return delaySubscription(f.OnSubscribe, trigger, subscriber, scheduler)
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
+
+// delaySubscription subscribes the subscriber to the source only after the
+// trigger emits an item or completes. Requests made in the meantime are
+// buffered and replayed to the source once it is subscribed.
+func delaySubscription(
+	source func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription,
+	trigger cesium.Publisher,
+	subscriber cesium.Subscriber,
+	scheduler cesium.Scheduler,
+) cesium.Subscription {
+	subscription := &BufferedProxySubscription{}
+	mux := sync.Mutex{}
+	started := false
+	cancelled := false
+
+	start := func() {
+		mux.Lock()
+		if started || cancelled {
+			mux.Unlock()
+			return
+		}
+		started = true
+		mux.Unlock()
+
+		subscription.SetSubscription(source(DoObserver(
+			subscriber.OnNext,
+			subscriber.OnComplete,
+			subscriber.OnError,
+		), scheduler))
+	}
+
+	triggerSubscription := FluxFrom(trigger).Subscribe(DoObserver(
+		func(cesium.T) {
+			start()
+		},
+		start,
+		func(err error) {
+			mux.Lock()
+			if started || cancelled {
+				mux.Unlock()
+				return
+			}
+			started = true
+			mux.Unlock()
+
+			subscriber.OnError(err)
+		},
+	))
+
+	sub := &Subscription{
+		CancelFunc: func() {
+			mux.Lock()
+			cancelled = true
+			mux.Unlock()
+
+			triggerSubscription.Cancel()
+			subscription.Cancel()
+		},
+		RequestFunc: func(n int64) {
+			subscription.Request(n)
+		},
+	}
+
+	subscriber.OnSubscribe(sub)
+	triggerSubscription.Request(1)
+	return sub
+}
+
+func (f *Flux) Collect(supplier func() cesium.T, accumulator func(cesium.T, cesium.T)) cesium.Mono {
+	return f.collect(supplier, accumulator, func(container cesium.T) cesium.T {
+		return container
+	})
+}
+
+func (f *Flux) CollectSlice() cesium.Mono {
+	return f.collect(
+		func() cesium.T {
+			return &[]cesium.T{}
+		},
+		func(container cesium.T, t cesium.T) {
+			slice := container.(*[]cesium.T)
+			*slice = append(*slice, t)
+		},
+		func(container cesium.T) cesium.T {
+			return *container.(*[]cesium.T)
+		},
+	)
+}
+
+func (f *Flux) CollectSortedSlice(less func(cesium.T, cesium.T) bool) cesium.Mono {
+	return f.collect(
+		func() cesium.T {
+			return &[]cesium.T{}
+		},
+		func(container cesium.T, t cesium.T) {
+			slice := container.(*[]cesium.T)
+			*slice = append(*slice, t)
+		},
+		func(container cesium.T) cesium.T {
+			slice := *container.(*[]cesium.T)
+			sort.SliceStable(slice, func(i, j int) bool {
+				return less(slice[i], slice[j])
+			})
+
+			return slice
+		},
+	)
+}
+
+func (f *Flux) CollectMap(keyFn func(cesium.T) cesium.T, valueFn func(cesium.T) cesium.T) cesium.Mono {
+	return f.collect(
+		func() cesium.T {
+			return map[cesium.T]cesium.T{}
+		},
+		func(container cesium.T, t cesium.T) {
+			container.(map[cesium.T]cesium.T)[keyFn(t)] = valueFn(t)
+		},
+		func(container cesium.T) cesium.T {
+			return container
+		},
+	)
+}
+
+func (f *Flux) CollectMultiMap(keyFn func(cesium.T) cesium.T, valueFn func(cesium.T) cesium.T) cesium.Mono {
+	return f.collect(
+		func() cesium.T {
+			return map[cesium.T][]cesium.T{}
+		},
+		func(container cesium.T, t cesium.T) {
+			m := container.(map[cesium.T][]cesium.T)
+			key := keyFn(t)
+			m[key] = append(m[key], valueFn(t))
+		},
+		func(container cesium.T) cesium.T {
+			return container
+		},
+	)
+}
+
+func (f *Flux) collect(supplier func() cesium.T, accumulator func(cesium.T, cesium.T), finisher func(cesium.T) cesium.T) cesium.Mono {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := CollectProcessor(supplier, accumulator, finisher)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := f.OnSubscribe(p, scheduler)
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(f, &Mono{OnSubscribe: onPublish})
 }
@@ -0,0 +1,887 @@
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// publishPrefetch is the amount of items the connection created by
+// Flux.Publish requests from the source in advance.
+const publishPrefetch = 256
+
+// connection is a single subscription of a ConnectableFlux to its source,
+// shared by all of its subscribers.
+type connection interface {
+	cesium.Disposable
+
+	// connect subscribes to the source. Returns false if the connection can
+	// not be used anymore, because it was disposed or has terminated.
+	connect() bool
+
+	// add registers the subscriber. Returns false if the connection can not
+	// be used anymore, because it was disposed or has terminated.
+	add(*connectionSubscriber) bool
+
+	// drain emits as much as the demand of the subscribers allows.
+	drain()
+}
+
+type drainer interface {
+	drain()
+}
+
+// connectionSubscriber is a subscriber of a ConnectableFlux along with its
+// own demand.
+type connectionSubscriber struct {
+	requested  int64 // first, to be aligned for the atomic operations
+	subscriber cesium.Subscriber
+
+	mux        sync.Mutex
+	cancelled  bool
+	connection drainer
+
+	// index of the next item to emit, only used (and guarded) by the
+	// replayConnection.
+	index int
+}
+
+func (s *connectionSubscriber) request(n int64) {
+	addDemandAtomic(&s.requested, n)
+
+	s.mux.Lock()
+	c := s.connection
+	s.mux.Unlock()
+
+	if c != nil {
+		c.drain()
+	}
+}
+
+func (s *connectionSubscriber) cancel() {
+	s.mux.Lock()
+	s.cancelled = true
+	c := s.connection
+	s.mux.Unlock()
+
+	// The demand of the remaining subscribers may allow more emissions now.
+	if c != nil {
+		c.drain()
+	}
+}
+
+func (s *connectionSubscriber) setConnection(c drainer) {
+	s.mux.Lock()
+	s.connection = c
+	s.mux.Unlock()
+}
+
+func (s *connectionSubscriber) isCancelled() bool {
+	s.mux.Lock()
+	cancelled := s.cancelled
+	s.mux.Unlock()
+
+	return cancelled
+}
+
+func (s *connectionSubscriber) demand() int64 {
+	return atomic.LoadInt64(&s.requested)
+}
+
+func (s *connectionSubscriber) emit(t cesium.T) {
+	s.mux.Lock()
+	if s.cancelled {
+		s.mux.Unlock()
+		return
+	}
+
+	s.mux.Unlock()
+
+	produceDemand(&s.requested, 1)
+
+	s.subscriber.OnNext(t)
+}
+
+func (s *connectionSubscriber) terminate(err error) {
+	if s.isCancelled() {
+		return
+	}
+
+	if err != nil {
+		s.subscriber.OnError(err)
+	} else {
+		s.subscriber.OnComplete()
+	}
+}
+
+// fail terminates the subscriber with the error and stops any further
+// emissions to it.
+func (s *connectionSubscriber) fail(err error) {
+	s.mux.Lock()
+	cancelled := s.cancelled
+	s.cancelled = true
+	s.mux.Unlock()
+
+	if !cancelled {
+		s.subscriber.OnError(err)
+	}
+}
+
+// ConnectableFlux is a Flux that only subscribes to its source when
+// connected, sharing the source emissions between all of its subscribers.
+type ConnectableFlux struct {
+	*Flux
+
+	mux           sync.Mutex
+	current       connection
+	newConnection func() connection
+}
+
+func newConnectableFlux(newConnection func() connection) *ConnectableFlux {
+	cf := &ConnectableFlux{newConnection: newConnection}
+	cf.Flux = &Flux{OnSubscribe: cf.subscribe}
+
+	return cf
+}
+
+func (cf *ConnectableFlux) connection() connection {
+	cf.mux.Lock()
+	if cf.current == nil {
+		cf.current = cf.newConnection()
+	}
+	c := cf.current
+	cf.mux.Unlock()
+
+	return c
+}
+
+func (cf *ConnectableFlux) reset(c connection) {
+	cf.mux.Lock()
+	if cf.current == c {
+		cf.current = nil
+	}
+	cf.mux.Unlock()
+}
+
+func (cf *ConnectableFlux) subscribe(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+	s := &connectionSubscriber{subscriber: subscriber}
+	sub := &Subscription{
+		CancelFunc:  s.cancel,
+		RequestFunc: s.request,
+	}
+
+	subscriber.OnSubscribe(sub)
+
+	for {
+		c := cf.connection()
+		if c.add(s) {
+			return sub
+		}
+
+		cf.reset(c)
+	}
+}
+
+func (cf *ConnectableFlux) Connect() cesium.Disposable {
+	for {
+		c := cf.connection()
+		if c.connect() {
+			return c
+		}
+
+		cf.reset(c)
+	}
+}
+
+func (cf *ConnectableFlux) AutoConnect(n int) cesium.Flux {
+	if n <= 0 {
+		cf.Connect()
+		return onFluxAssembly(cf, &Flux{OnSubscribe: cf.subscribe})
+	}
+
+	mux := sync.Mutex{}
+	count := 0
+
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		sub := cf.subscribe(subscriber, scheduler)
+
+		mux.Lock()
+		count++
+		connect := count == n
+		mux.Unlock()
+
+		if connect {
+			cf.Connect()
+		}
+
+		return sub
+	}
+
+	return onFluxAssembly(cf, &Flux{OnSubscribe: onPublish})
+}
+
+func (cf *ConnectableFlux) RefCount(n int, gracePeriod time.Duration) cesium.Flux {
+	return onFluxAssembly(cf, &Flux{OnSubscribe: cf.refCount(n, gracePeriod)})
+}
+
+// refCount returns the function subscribing to the ConnectableFlux, which
+// connects once there are n subscribers and disconnects after the grace
+// period once all of them are gone.
+func (cf *ConnectableFlux) refCount(n int, gracePeriod time.Duration) func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription {
+	mux := sync.Mutex{}
+	count := 0
+	var current cesium.Disposable
+	var timer cesium.Cancellable
+	scheduler := TimerScheduler()
+
+	disconnect := func(c cesium.Disposable) {
+		mux.Lock()
+		if count > 0 || current != c {
+			mux.Unlock()
+			return
+		}
+		current = nil
+		mux.Unlock()
+
+		c.Dispose()
+	}
+
+	release := func() {
+		mux.Lock()
+		count--
+		c := current
+		if count > 0 || c == nil {
+			mux.Unlock()
+			return
+		}
+
+		if gracePeriod <= 0 {
+			mux.Unlock()
+			disconnect(c)
+			return
+		}
+
+		timer = scheduler.ScheduleAfter(gracePeriod, func(canceller cesium.Canceller) {
+			if !canceller.IsCancelled() {
+				disconnect(c)
+			}
+		})
+		mux.Unlock()
+	}
+
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		once := sync.Once{}
+		rs := &refCountSubscriber{
+			Subscriber: subscriber,
+			release: func() {
+				once.Do(release)
+			},
+		}
+
+		cf.subscribe(rs, scheduler)
+
+		mux.Lock()
+		count++
+		if timer != nil {
+			timer.Cancel()
+			timer = nil
+		}
+		connect := count >= n && current == nil
+		mux.Unlock()
+
+		if connect {
+			c := cf.Connect()
+
+			mux.Lock()
+			current = c
+			mux.Unlock()
+		}
+
+		return rs.subscription
+	}
+
+	return onPublish
+}
+
+// refCountSubscriber releases its slot in the RefCount when it terminates or
+// its subscription is cancelled.
+type refCountSubscriber struct {
+	cesium.Subscriber
+	release      func()
+	subscription cesium.Subscription
+}
+
+func (s *refCountSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	s.subscription = &Subscription{
+		CancelFunc: func() {
+			subscription.Cancel()
+			s.release()
+		},
+		RequestFunc: func(n int64) {
+			subscription.Request(n)
+		},
+	}
+
+	s.Subscriber.OnSubscribe(s.subscription)
+}
+
+func (s *refCountSubscriber) OnComplete() {
+	s.release()
+	s.Subscriber.OnComplete()
+}
+
+func (s *refCountSubscriber) OnError(err error) {
+	s.release()
+	s.Subscriber.OnError(err)
+}
+
+// publishConnection multicasts the source emissions to its subscribers. It
+// requests the items from the source in advance, keeping them in a bounded
+// queue, and emits them at the pace of the slowest subscriber.
+type publishConnection struct {
+	source   cesium.Publisher
+	prefetch int64
+
+	mux          sync.Mutex
+	subscribers  []*connectionSubscriber
+	queue        []cesium.T
+	subscription cesium.Subscription
+	consumed     int64
+	connected    bool
+	disposed     bool
+	done         bool
+	err          error
+	terminated   bool
+	draining     bool
+	missed       bool
+}
+
+func (c *publishConnection) connect() bool {
+	c.mux.Lock()
+	if c.disposed || c.terminated {
+		c.mux.Unlock()
+		return false
+	}
+
+	if c.connected {
+		c.mux.Unlock()
+		return true
+	}
+	c.connected = true
+	c.mux.Unlock()
+
+	c.setSubscription(c.source.Subscribe(DoObserver(c.next, c.complete, c.error)))
+
+	return true
+}
+
+// setSubscription requests the prefetched amount of items from the source
+// subscription. Any subscription but the first one gets cancelled.
+func (c *publishConnection) setSubscription(subscription cesium.Subscription) {
+	c.mux.Lock()
+	if c.subscription != nil {
+		c.mux.Unlock()
+		subscription.Cancel()
+		return
+	}
+
+	c.subscription = subscription
+	cancel := c.disposed || c.done
+	c.mux.Unlock()
+
+	if cancel {
+		subscription.Cancel()
+	} else {
+		subscription.Request(c.prefetch)
+	}
+}
+
+func (c *publishConnection) next(t cesium.T) {
+	if c.tryNext(t) != cesium.EmitResultFailOverflow {
+		return
+	}
+
+	// The source ignored the backpressure.
+	c.mux.Lock()
+	subscription := c.subscription
+	if !c.done {
+		c.done = true
+		c.err = cesium.DownstreamUnableToKeepUpError
+	}
+	c.mux.Unlock()
+
+	if subscription != nil {
+		subscription.Cancel()
+	}
+	c.drain()
+}
+
+// tryNext queues the item unless the connection has terminated or the queue
+// is full.
+func (c *publishConnection) tryNext(t cesium.T) cesium.EmitResult {
+	c.mux.Lock()
+	if c.done {
+		c.mux.Unlock()
+		return cesium.EmitResultFailTerminated
+	}
+
+	if int64(len(c.queue)) >= c.prefetch {
+		c.mux.Unlock()
+		return cesium.EmitResultFailOverflow
+	}
+
+	c.queue = append(c.queue, t)
+	c.mux.Unlock()
+	c.drain()
+
+	return cesium.EmitResultOK
+}
+
+func (c *publishConnection) complete() {
+	c.mux.Lock()
+	c.done = true
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *publishConnection) error(err error) {
+	c.mux.Lock()
+	if !c.done {
+		c.done = true
+		c.err = err
+	}
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *publishConnection) add(s *connectionSubscriber) bool {
+	c.mux.Lock()
+	if c.disposed || c.terminated {
+		c.mux.Unlock()
+		return false
+	}
+
+	c.subscribers = append(c.subscribers, s)
+	s.setConnection(c)
+	c.mux.Unlock()
+
+	c.drain()
+	return true
+}
+
+func (c *publishConnection) Dispose() {
+	c.mux.Lock()
+	if c.disposed || c.terminated {
+		c.mux.Unlock()
+		return
+	}
+
+	c.disposed = true
+	c.done = true
+	c.err = cesium.DisconnectedError
+	c.queue = nil
+	subscription := c.subscription
+	c.mux.Unlock()
+
+	if subscription != nil {
+		subscription.Cancel()
+	}
+
+	c.drain()
+}
+
+func (c *publishConnection) IsDisposed() bool {
+	c.mux.Lock()
+	disposed := c.disposed
+	c.mux.Unlock()
+
+	return disposed
+}
+
+func (c *publishConnection) drain() {
+	c.mux.Lock()
+	if c.draining {
+		c.missed = true
+		c.mux.Unlock()
+		return
+	}
+	c.draining = true
+
+	for {
+		c.missed = false
+
+		var subscribers []*connectionSubscriber
+		for _, s := range c.subscribers {
+			if !s.isCancelled() {
+				subscribers = append(subscribers, s)
+			}
+		}
+		c.subscribers = subscribers
+
+		if len(subscribers) > 0 {
+			n := int64(len(c.queue))
+			for _, s := range subscribers {
+				if d := s.demand(); d < n {
+					n = d
+				}
+			}
+
+			for ; n > 0 && len(c.queue) > 0; n-- {
+				item := c.queue[0]
+				c.queue = c.queue[1:]
+
+				replenish := int64(0)
+				c.consumed++
+				if c.consumed >= c.prefetch-c.prefetch>>2 {
+					replenish = c.consumed
+					c.consumed = 0
+				}
+				subscription := c.subscription
+				c.mux.Unlock()
+
+				for _, s := range subscribers {
+					s.emit(item)
+				}
+
+				if replenish > 0 && subscription != nil {
+					subscription.Request(replenish)
+				}
+
+				c.mux.Lock()
+			}
+		}
+
+		if c.done && len(c.queue) == 0 && !c.terminated {
+			c.terminated = true
+			subscribers := c.subscribers
+			c.subscribers = nil
+			err := c.err
+			c.mux.Unlock()
+
+			for _, s := range subscribers {
+				s.terminate(err)
+			}
+
+			return
+		}
+
+		if !c.missed {
+			c.draining = false
+			c.mux.Unlock()
+			return
+		}
+	}
+}
+
+func (f *Flux) Publish() cesium.ConnectableFlux {
+	return newConnectableFlux(func() connection {
+		return &publishConnection{
+			source:   f,
+			prefetch: publishPrefetch,
+		}
+	})
+}
+
+type replayedItem struct {
+	item      cesium.T
+	timestamp time.Time
+}
+
+// replayConnection records the source emissions and replays them to each of
+// its subscribers at their own pace, so it requests the source in unbounded
+// mode. The late subscribers receive the last history items (or all of them
+// if history is negative) that are not older than the ttl (if set), followed
+// by the live emissions.
+type replayConnection struct {
+	source  cesium.Publisher
+	history int
+	ttl     time.Duration
+	clock   cesium.Clock
+
+	// expires makes the connection unusable after the ttl passes from the
+	// termination of the source, so the next subscriber resubscribes.
+	expires bool
+
+	mux          sync.Mutex
+	subscribers  []*connectionSubscriber
+	items        []replayedItem
+	offset       int
+	subscription cesium.Subscription
+	connected    bool
+	disposed     bool
+	done         bool
+	doneAt       time.Time
+	err          error
+	draining     bool
+	missed       bool
+}
+
+func (c *replayConnection) usable() bool {
+	if c.disposed {
+		return false
+	}
+
+	if c.expires && c.done && c.ttl > 0 && c.clock.Now().Sub(c.doneAt) > c.ttl {
+		return false
+	}
+
+	return true
+}
+
+func (c *replayConnection) connect() bool {
+	c.mux.Lock()
+	if !c.usable() {
+		c.mux.Unlock()
+		return false
+	}
+
+	if c.connected {
+		c.mux.Unlock()
+		return true
+	}
+	c.connected = true
+	c.mux.Unlock()
+
+	c.setSubscription(c.source.Subscribe(DoObserver(c.next, c.complete, c.error)))
+
+	return true
+}
+
+// setSubscription requests all the items from the source subscription. Any
+// subscription but the first one gets cancelled.
+func (c *replayConnection) setSubscription(subscription cesium.Subscription) {
+	c.mux.Lock()
+	if c.subscription != nil {
+		c.mux.Unlock()
+		subscription.Cancel()
+		return
+	}
+
+	c.subscription = subscription
+	cancel := c.disposed || c.done
+	c.mux.Unlock()
+
+	if cancel {
+		subscription.Cancel()
+	} else {
+		subscription.RequestUnbounded()
+	}
+}
+
+func (c *replayConnection) next(t cesium.T) {
+	c.tryNext(t)
+}
+
+// tryNext records the item unless the connection has terminated.
+func (c *replayConnection) tryNext(t cesium.T) cesium.EmitResult {
+	c.mux.Lock()
+	if c.done {
+		c.mux.Unlock()
+		return cesium.EmitResultFailTerminated
+	}
+
+	c.items = append(c.items, replayedItem{t, c.clock.Now()})
+	c.mux.Unlock()
+	c.drain()
+
+	return cesium.EmitResultOK
+}
+
+func (c *replayConnection) complete() {
+	c.mux.Lock()
+	if c.done {
+		c.mux.Unlock()
+		return
+	}
+
+	c.done = true
+	c.doneAt = c.clock.Now()
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *replayConnection) error(err error) {
+	c.mux.Lock()
+	if c.done {
+		c.mux.Unlock()
+		return
+	}
+
+	c.done = true
+	c.doneAt = c.clock.Now()
+	c.err = err
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *replayConnection) add(s *connectionSubscriber) bool {
+	c.mux.Lock()
+	if !c.usable() {
+		c.mux.Unlock()
+		return false
+	}
+
+	s.index = c.start()
+	c.subscribers = append(c.subscribers, s)
+	s.setConnection(c)
+	c.mux.Unlock()
+
+	c.drain()
+	return true
+}
+
+// start returns the index of the first item to replay to a new subscriber.
+func (c *replayConnection) start() int {
+	start := c.offset
+	if c.history >= 0 && len(c.items) > c.history {
+		start = c.offset + len(c.items) - c.history
+	}
+
+	if c.ttl > 0 {
+		now := c.clock.Now()
+		for start < c.offset+len(c.items) && now.Sub(c.items[start-c.offset].timestamp) > c.ttl {
+			start++
+		}
+	}
+
+	return start
+}
+
+// trim drops the items that will not be replayed to anyone anymore.
+func (c *replayConnection) trim() {
+	min := c.start()
+	for _, s := range c.subscribers {
+		if s.index < min {
+			min = s.index
+		}
+	}
+
+	if min > c.offset {
+		c.items = c.items[min-c.offset:]
+		c.offset = min
+	}
+}
+
+func (c *replayConnection) Dispose() {
+	c.mux.Lock()
+	if c.disposed || c.done {
+		c.disposed = true
+		c.mux.Unlock()
+		return
+	}
+
+	c.disposed = true
+	c.done = true
+	c.doneAt = c.clock.Now()
+	c.err = cesium.DisconnectedError
+	subscription := c.subscription
+	c.mux.Unlock()
+
+	if subscription != nil {
+		subscription.Cancel()
+	}
+
+	c.drain()
+}
+
+func (c *replayConnection) IsDisposed() bool {
+	c.mux.Lock()
+	disposed := c.disposed
+	c.mux.Unlock()
+
+	return disposed
+}
+
+func (c *replayConnection) drain() {
+	c.mux.Lock()
+	if c.draining {
+		c.missed = true
+		c.mux.Unlock()
+		return
+	}
+	c.draining = true
+
+	for {
+		c.missed = false
+
+		finished := map[*connectionSubscriber]bool{}
+		subscribers := c.subscribers
+		for _, s := range subscribers {
+			for s.index < c.offset+len(c.items) && s.demand() > 0 && !s.isCancelled() {
+				item := c.items[s.index-c.offset].item
+				s.index++
+				c.mux.Unlock()
+
+				s.emit(item)
+
+				c.mux.Lock()
+			}
+
+			if s.isCancelled() {
+				finished[s] = true
+				continue
+			}
+
+			if c.done && s.index >= c.offset+len(c.items) {
+				finished[s] = true
+				err := c.err
+				c.mux.Unlock()
+
+				s.terminate(err)
+
+				c.mux.Lock()
+			}
+		}
+
+		var remaining []*connectionSubscriber
+		for _, s := range c.subscribers {
+			if !finished[s] {
+				remaining = append(remaining, s)
+			}
+		}
+		c.subscribers = remaining
+		c.trim()
+
+		if !c.missed {
+			c.draining = false
+			c.mux.Unlock()
+			return
+		}
+	}
+}
+
+func (f *Flux) Replay(history int) cesium.ConnectableFlux {
+	return newConnectableFlux(func() connection {
+		return &replayConnection{
+			source:  f,
+			history: history,
+			clock:   TimerScheduler(),
+		}
+	})
+}
+
+func (f *Flux) Cache() cesium.Flux {
+	return f.Replay(-1).AutoConnect(1)
+}
+
+func (f *Flux) CacheWithTTL(ttl time.Duration, scheduler ...cesium.TimedScheduler) cesium.Flux {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return newConnectableFlux(func() connection {
+		return &replayConnection{
+			source:  f,
+			history: -1,
+			ttl:     ttl,
+			clock:   sch,
+		}
+	}).AutoConnect(1)
+}
+
+func (f *Flux) Share() cesium.Flux {
+	return f.Publish().RefCount(1, 0)
+}
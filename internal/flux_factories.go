@@ -1,11 +1,8 @@
 package internal
 
 import (
-	"math"
 	"sync"
 
-	"time"
-
 	"github.com/DusanKasan/cesium"
 )
 
@@ -25,50 +22,26 @@ func FluxJust(items ...cesium.T) cesium.Flux {
 // behaves like a mono, and offers more efficient implementation of some operators.
 func fluxFromCallable(f func() (cesium.T, bool)) cesium.Flux {
 	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		if scheduler == nil {
-			scheduler = SeparateGoroutineScheduler()
-		}
-
-		mux := sync.Mutex{}
-		requested := false
-
-		cancellable := scheduler.Schedule(func(canceller cesium.Canceller) {
-			for {
-				if canceller.IsCancelled() {
-					return
-				}
-
-				mux.Lock()
-				if requested {
-					mux.Unlock()
-
-					if canceller.IsCancelled() {
-						return
-					}
+		d := newDrainLoop(scheduler)
+		d.emit = func() (bool, bool) {
+			var t cesium.T
+			var ok bool
+			if err := callOperator(func() { t, ok = f() }, nil); err != nil {
+				subscriber.OnError(err)
+				return false, true
+			}
 
-					t, ok := f()
-					if ok {
-						subscriber.OnNext(t)
-					}
-					subscriber.OnComplete()
-					return
-				} else {
-					mux.Unlock()
-				}
+			if ok {
+				subscriber.OnNext(t)
 			}
-		})
 
-		sub := &Subscription{
-			CancelFunc: func() {
-				cancellable.Cancel()
-			},
-			RequestFunc: func(n int64) {
-				mux.Lock()
-				requested = true
-				mux.Unlock()
-			},
+			if !d.isCancelled() {
+				subscriber.OnComplete()
+			}
+			return true, true
 		}
 
+		sub := d.subscription()
 		subscriber.OnSubscribe(sub)
 		return sub
 	}
@@ -91,183 +64,23 @@ func FluxFromSlice(items []cesium.T) cesium.Flux {
 		})
 	}
 
-	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		if scheduler == nil {
-			scheduler = SeparateGoroutineScheduler()
-		}
-
-		mux := sync.Mutex{}
+	return newFuseableFlux(func(func(error, cesium.T)) *fusedQueue {
 		index := 0
-		requested := int64(0)
-		unbounded := false
-
-		switch s := subscriber.(type) {
-		case ConditionalSubscriber:
-			fastPath := func(canceller cesium.Canceller) {
-				for ; index < len(items); index++ {
-					if canceller.IsCancelled() {
-						return
-					}
-
-					s.OnNextIf(items[index])
-				}
-
-				if canceller.IsCancelled() {
-					return
-				}
 
-				s.OnComplete()
-			}
-
-			cancellable := scheduler.Schedule(func(canceller cesium.Canceller) {
-			sliceFor:
-				for ; index < len(items); index++ {
-					for {
-						if canceller.IsCancelled() {
-							return
-						}
-
-						mux.Lock()
-						if unbounded {
-							mux.Unlock()
-							fastPath(canceller)
-							return
-						}
-						mux.Unlock()
-
-						mux.Lock()
-						if requested > 0 {
-							requested = requested - 1
-							mux.Unlock()
-
-							if canceller.IsCancelled() {
-								return
-							}
-
-							if !s.OnNextIf(items[index]) {
-								mux.Lock()
-								requested = requested + 1
-								mux.Unlock()
-							}
-							continue sliceFor
-						} else {
-							mux.Unlock()
-						}
-					}
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				if index == len(items) {
+					return nil, false, nil
 				}
 
-				if canceller.IsCancelled() {
-					return
-				}
-
-				s.OnComplete()
-			})
-
-			sub := &Subscription{
-				CancelFunc: func() {
-					cancellable.Cancel()
-				},
-				RequestFunc: func(n int64) {
-					mux.Lock()
-					if unbounded {
-						mux.Unlock()
-						return
-					}
-
-					if n == math.MaxInt64 {
-						unbounded = true
-					} else {
-						requested = requested + n
-					}
-					mux.Unlock()
-				},
-			}
-
-			s.OnSubscribe(sub)
-			return sub
-		default:
-			fastPath := func(canceller cesium.Canceller) {
-				for ; index < len(items); index++ {
-					if canceller.IsCancelled() {
-						return
-					}
-
-					subscriber.OnNext(items[index])
-				}
-
-				if canceller.IsCancelled() {
-					return
-				}
-
-				subscriber.OnComplete()
-			}
-
-			cancellable := scheduler.Schedule(func(canceller cesium.Canceller) {
-			sliceFor:
-				for ; index < len(items); index++ {
-					for {
-						if canceller.IsCancelled() {
-							return
-						}
-
-						mux.Lock()
-						if unbounded {
-							mux.Unlock()
-							fastPath(canceller)
-							return
-						}
-						mux.Unlock()
-
-						mux.Lock()
-						if requested > 0 {
-							requested = requested - 1
-							mux.Unlock()
-
-							if canceller.IsCancelled() {
-								return
-							}
-
-							subscriber.OnNext(items[index])
-							continue sliceFor
-						} else {
-							mux.Unlock()
-						}
-					}
-				}
-
-				if canceller.IsCancelled() {
-					return
-				}
-
-				subscriber.OnComplete()
-			})
-
-			sub := &Subscription{
-				CancelFunc: func() {
-					cancellable.Cancel()
-				},
-				RequestFunc: func(n int64) {
-					mux.Lock()
-					if unbounded {
-						mux.Unlock()
-						return
-					}
-
-					if n == math.MaxInt64 {
-						unbounded = true
-					} else {
-						requested = requested + n
-					}
-					mux.Unlock()
-				},
-			}
-
-			subscriber.OnSubscribe(sub)
-			return sub
+				index++
+				return items[index-1], true, nil
+			},
+			isEmpty: func() bool {
+				return index == len(items)
+			},
 		}
-	}
-
-	return &Flux{OnSubscribe: onPublish}
+	})
 }
 
 // Range creates new cesium.Flux that emits 64bit integers from start to
@@ -281,166 +94,25 @@ func FluxRange(start int, count int) cesium.Flux {
 		return FluxJust(start)
 	}
 
-	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		if scheduler == nil {
-			scheduler = SeparateGoroutineScheduler()
-		}
+	end := int64(start) + int64(count)
 
-		mux := sync.Mutex{}
-		requested := int64(0)
-		unbounded := false
+	return newFuseableFlux(func(func(error, cesium.T)) *fusedQueue {
 		item := int64(start)
 
-		switch s := subscriber.(type) {
-		case ConditionalSubscriber:
-			cancellable := scheduler.Schedule(func(canceller cesium.Canceller) {
-			sliceFor:
-				for ; item < int64(start)+int64(count); item++ {
-					for {
-						if canceller.IsCancelled() {
-							return
-						}
-
-						mux.Lock()
-						if unbounded {
-							mux.Unlock()
-							for ; item < int64(start)+int64(count); item++ {
-								if canceller.IsCancelled() {
-									return
-								}
-
-								s.OnNextIf(item)
-							}
-
-							s.OnComplete()
-							return
-						}
-						mux.Unlock()
-
-						mux.Lock()
-						if requested > 0 {
-							requested = requested - 1
-							mux.Unlock()
-							if canceller.IsCancelled() {
-								return
-							}
-
-							if !s.OnNextIf(item) {
-								mux.Lock()
-								requested = requested + 1
-								mux.Unlock()
-							}
-
-							continue sliceFor
-						} else {
-							mux.Unlock()
-						}
-					}
-				}
-
-				if canceller.IsCancelled() {
-					return
-				}
-
-				s.OnComplete()
-			})
-
-			sub := &Subscription{
-				CancelFunc: func() {
-					cancellable.Cancel()
-				},
-				RequestFunc: func(n int64) {
-					mux.Lock()
-					if unbounded {
-						mux.Unlock()
-						return
-					}
-
-					if n == math.MaxInt64 {
-						unbounded = true
-					} else {
-						requested = requested + n
-					}
-					mux.Unlock()
-				},
-			}
-
-			s.OnSubscribe(sub)
-			return sub
-		default:
-			cancellable := scheduler.Schedule(func(canceller cesium.Canceller) {
-			sliceFor:
-				for ; item < int64(start)+int64(count); item++ {
-					for {
-						if canceller.IsCancelled() {
-							return
-						}
-
-						mux.Lock()
-						if unbounded {
-							mux.Unlock()
-							for ; item < int64(start)+int64(count); item++ {
-								if canceller.IsCancelled() {
-									return
-								}
-
-								subscriber.OnNext(item)
-							}
-
-							subscriber.OnComplete()
-							return
-						}
-						mux.Unlock()
-
-						mux.Lock()
-						if requested > 0 {
-							requested = requested - 1
-							mux.Unlock()
-							if canceller.IsCancelled() {
-								return
-							}
-							subscriber.OnNext(item)
-							continue sliceFor
-						} else {
-							mux.Unlock()
-						}
-					}
-				}
-
-				if canceller.IsCancelled() {
-					return
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				if item == end {
+					return nil, false, nil
 				}
 
-				subscriber.OnComplete()
-			})
-
-			sub := &Subscription{
-				CancelFunc: func() {
-					cancellable.Cancel()
-				},
-				RequestFunc: func(n int64) {
-					mux.Lock()
-					if unbounded {
-						mux.Unlock()
-						return
-					}
-
-					if n == math.MaxInt64 {
-						unbounded = true
-					} else {
-						requested = requested + n
-					}
-					mux.Unlock()
-				},
-			}
-
-			subscriber.OnSubscribe(sub)
-			return sub
+				item++
+				return item - 1, true, nil
+			},
+			isEmpty: func() bool {
+				return item == end
+			},
 		}
-
-	}
-
-	return &Flux{OnSubscribe: onPublish}
+	})
 }
 
 // Empty creates new cesium.Flux that emits no items and completes normally.
@@ -482,7 +154,7 @@ func FluxError(err error) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{OnSubscribe: onPublish}
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish})
 
 }
 
@@ -506,7 +178,7 @@ func FluxNever() cesium.Flux {
 		return sub
 	}
 
-	return &Flux{OnSubscribe: onPublish}
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish})
 
 }
 
@@ -522,8 +194,13 @@ func FluxDefer(f func() cesium.Publisher) cesium.Flux {
 		subscriptionMux := sync.Mutex{}
 
 		canc := scheduler.Schedule(func(c cesium.Canceller) {
+			var publisher cesium.Publisher
+			if err := callOperator(func() { publisher = f() }, nil); err != nil {
+				publisher = FluxError(err)
+			}
+
 			subscriptionMux.Lock()
-			subscription = f().Subscribe(subscriber)
+			subscription = FluxFrom(publisher).Subscribe(subscriber)
 			subscriptionMux.Unlock()
 		})
 
@@ -550,7 +227,7 @@ func FluxDefer(f func() cesium.Publisher) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{OnSubscribe: onPublish}
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish})
 }
 
 // Using uses a resource, generated by a supplier for each individual Subscriber,
@@ -563,8 +240,12 @@ func FluxUsing(resourceSupplier func() cesium.T, sourceSupplier func(cesium.T) c
 			scheduler = SeparateGoroutineScheduler()
 		}
 
+		var resource cesium.T
+		if err := callOperator(func() { resource = resourceSupplier() }, nil); err != nil {
+			return FluxError(err).Subscribe(subscriber)
+		}
+
 		subscription := &BufferedProxySubscription{}
-		resource := resourceSupplier()
 
 		canc := scheduler.Schedule(func(c cesium.Canceller) {
 			p := DoFinallyProcessor(func() {
@@ -583,7 +264,12 @@ func FluxUsing(resourceSupplier func() cesium.T, sourceSupplier func(cesium.T) c
 				},
 			))
 
-			secondarySubscription := sourceSupplier(resource).Subscribe(p)
+			var source cesium.Publisher
+			if err := callOperator(func() { source = sourceSupplier(resource) }, nil); err != nil {
+				source = FluxError(err)
+			}
+
+			secondarySubscription := FluxFrom(source).Subscribe(p)
 			p.OnSubscribe(secondarySubscription)
 
 			subscription.SetSubscription(s)
@@ -603,7 +289,7 @@ func FluxUsing(resourceSupplier func() cesium.T, sourceSupplier func(cesium.T) c
 		return sub
 	}
 
-	return &Flux{OnSubscribe: onPublish}
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish})
 
 }
 
@@ -633,7 +319,9 @@ func FluxCreate(f func(cesium.FluxSink), overflowStrategy OverflowStrategy) cesi
 			}
 			sinkMux.Unlock()
 
-			f(sink)
+			if err := callOperator(func() { f(sink) }, nil); err != nil {
+				sink.Error(err)
+			}
 		})
 
 		for {
@@ -658,205 +346,79 @@ func FluxCreate(f func(cesium.FluxSink), overflowStrategy OverflowStrategy) cesi
 		return sub
 	}
 
-	return &Flux{OnSubscribe: onPublish}
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish})
 }
 
 func FluxGenerate(f func(cesium.SynchronousSink)) cesium.Flux {
-	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		if scheduler == nil {
-			scheduler = SeparateGoroutineScheduler()
-		}
+	return newFuseableFlux(func(func(error, cesium.T)) *fusedQueue {
+		done := false
 
-		requested := int64(0)
-		requestedMux := sync.Mutex{}
-		unbounded := false
-
-		switch s := subscriber.(type) {
-		case ConditionalSubscriber:
-			cancellable := scheduler.Schedule(func(c cesium.Canceller) {
-				for !c.IsCancelled() {
-					requestedMux.Lock()
-					if requested > 0 || unbounded {
-						requested = requested - 1
-						sink := &SynchronousSink{}
-
-						f(sink)
-
-						if !c.IsCancelled() {
-							sig := sink.Signal()
-							if sig == nil {
-								s.OnError(cesium.NoEmissionOnSynchronousSinkError)
-								requestedMux.Unlock()
-								return
-							}
-
-							switch sig.Type() {
-							case cesium.SignalTypeOnNext:
-								if !s.OnNextIf(sig.Item()) {
-									requested = requested + 1
-								}
-								requestedMux.Unlock()
-							case cesium.SignalTypeOnComplete, cesium.SignalTypeOnError:
-								sig.Accept(s)
-								requestedMux.Unlock()
-								return
-							}
-						}
-					}
-					requestedMux.Unlock()
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				if done {
+					return nil, false, nil
 				}
-			})
-
-			sub := &Subscription{
-				CancelFunc: func() {
-					cancellable.Cancel()
-				},
-				RequestFunc: func(n int64) {
-					requestedMux.Lock()
-					if unbounded {
-						requestedMux.Unlock()
-						return
-					}
-
-					if n == math.MaxInt64 {
-						unbounded = true
-					}
-
-					requested = requested + n
-					requestedMux.Unlock()
-				},
-			}
 
-			s.OnSubscribe(sub)
-			return sub
-		default:
-			cancellable := scheduler.Schedule(func(c cesium.Canceller) {
-				for !c.IsCancelled() {
-					requestedMux.Lock()
-					if requested > 0 || unbounded {
-						requested = requested - 1
-						sink := &SynchronousSink{}
-
-						f(sink)
-
-						if !c.IsCancelled() {
-							sig := sink.Signal()
-							if sig == nil {
-								subscriber.OnError(cesium.NoEmissionOnSynchronousSinkError)
-								requestedMux.Unlock()
-								return
-							}
-
-							sig.Accept(subscriber)
-
-							if sig.IsTerminal() {
-								requestedMux.Unlock()
-								return
-							}
-						}
-					}
-					requestedMux.Unlock()
+				sink := &SynchronousSink{}
+				if err := callOperator(func() { f(sink) }, nil); err != nil {
+					done = true
+					return nil, false, err
 				}
-			})
 
-			sub := &Subscription{
-				CancelFunc: func() {
-					cancellable.Cancel()
-				},
-				RequestFunc: func(n int64) {
-					requestedMux.Lock()
-					if unbounded {
-						requestedMux.Unlock()
-						return
-					}
-
-					if n == math.MaxInt64 {
-						unbounded = true
-					}
-
-					requested = requested + n
-					requestedMux.Unlock()
-				},
-			}
+				sig := sink.Signal()
+				if sig == nil {
+					done = true
+					return nil, false, cesium.NoEmissionOnSynchronousSinkError
+				}
 
-			subscriber.OnSubscribe(sub)
-			return sub
+				switch sig.Type() {
+				case cesium.SignalTypeOnNext:
+					return sig.Item(), true, nil
+				default:
+					done = true
+					return nil, false, sig.Error()
+				}
+			},
+			isEmpty: func() bool {
+				return done
+			},
 		}
-	}
-
-	return &Flux{OnSubscribe: onPublish}
+	})
 }
 
+// FluxFromChannel creates new cesium.Flux that emits the items received from
+// the channel and completes when it is closed, see channelSource.
 func FluxFromChannel(ch <-chan cesium.T) cesium.Flux {
-	subscribed := false
-	mux := sync.Mutex{}
+	c := &channelSource{ch: ch}
 
-	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		mux.Lock()
-		if subscribed {
-			panic("can not resubscribe to flux made from a channel")
-		}
-
-		subscribed = true
-		mux.Unlock()
+	return onFluxAssembly(nil, &Flux{OnSubscribe: c.subscribe})
+}
 
-		if scheduler == nil {
-			scheduler = SeparateGoroutineScheduler()
+// FluxMergeDelayError creates new cesium.Flux that emits the items of all the
+// supplied publishers as they arrive. An error from one of them does not
+// terminate the merged sequence, all the errors are emitted as a
+// cesium.CompositeError once every publisher terminates.
+func FluxMergeDelayError(publishers ...cesium.Publisher) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		var pubs []cesium.T
+		for _, pub := range publishers {
+			pubs = append(pubs, pub)
 		}
 
-		requestedMux := sync.Mutex{}
-		requested := int64(0)
-		unbounded := false
+		p := FlatMapDelayErrorProcessor(func(t cesium.T) cesium.Publisher {
+			return t.(cesium.Publisher)
+		}, SeparateGoroutineScheduler())
 
-		canc := scheduler.Schedule(func(c cesium.Canceller) {
-			if c.IsCancelled() {
-				return
-			}
-
-			for {
-				requestedMux.Lock()
-				if requested == 0 && !unbounded {
-					requestedMux.Unlock()
-					continue
-				}
-				requestedMux.Unlock()
-
-				select {
-				case t, ok := <-ch:
-					if c.IsCancelled() {
-						return
-					}
-
-					if ok {
-						requestedMux.Lock()
-						requested--
-						requestedMux.Unlock()
-						subscriber.OnNext(t)
-					} else {
-						subscriber.OnComplete()
-						return
-					}
-				case <-time.After(time.Millisecond):
-					if c.IsCancelled() {
-						return
-					}
-				}
-			}
-		})
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := FluxFromSlice(pubs).Subscribe(p)
 
 		sub := &Subscription{
 			CancelFunc: func() {
-				canc.Cancel()
+				subscription1.Cancel()
+				subscription2.Cancel()
 			},
 			RequestFunc: func(n int64) {
-				requestedMux.Lock()
-				if unbounded {
-					requestedMux.Unlock()
-					return
-				}
-
-				requested = requested + n
-				requestedMux.Unlock()
+				subscription1.Request(n)
 			},
 		}
 
@@ -864,5 +426,5 @@ func FluxFromChannel(ch <-chan cesium.T) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{OnSubscribe: onPublish}
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish})
 }
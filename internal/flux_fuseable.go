@@ -0,0 +1,260 @@
+package internal
+
+import "github.com/DusanKasan/cesium"
+
+// fusedQueue is the queue the items of a fused chain are pulled from. A new
+// one is created for each subscription.
+type fusedQueue struct {
+	// poll returns the next item, false once there are no more items, or the
+	// error terminating the sequence.
+	poll func() (cesium.T, bool, error)
+
+	// isEmpty reports whether poll is known to return no more items, without
+	// pulling any. It may report false even if the remaining items are going
+	// to be filtered out.
+	isEmpty func() bool
+}
+
+// FuseableFlux is a cesium.Flux of a synchronous source whose items can be
+// pulled from a fusedQueue. The Map, Filter, Handle and DistinctUntilChanged
+// operators applied to it fuse into the queue, so that the whole chain is
+// drained by a single loop, without any OnNext or Request calls between the
+// operators.
+type FuseableFlux struct {
+	*Flux
+	queue func(errorContinue func(error, cesium.T)) *fusedQueue
+}
+
+// newFuseableFlux returns a FuseableFlux pulling from the queues, unless the
+// operators can not be fused at the moment, see canFuse.
+func newFuseableFlux(queue func(errorContinue func(error, cesium.T)) *fusedQueue) cesium.Flux {
+	f := &FuseableFlux{queue: queue}
+	f.Flux = &Flux{OnSubscribe: f.subscribe}
+
+	if !canFuse() {
+		return onFluxAssembly(nil, f.Flux)
+	}
+
+	return f
+}
+
+// canFuse reports whether the operators can be fused. They are not while they
+// are decorated or traced, so that each of them is seen by the hooks.
+func canFuse() bool {
+	return operatorDecorators() == nil && !isOperatorDebug()
+}
+
+// subscribe drains the queue to the subscriber on the scheduler. The items
+// that panic in the fused operators are passed to the OnErrorContinue
+// strategy of the subscriber, if there is one.
+func (f *FuseableFlux) subscribe(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+	q := f.queue(errorContinueOf(subscriber))
+	onNext := onNextOf(subscriber)
+
+	d := newDrainLoop(scheduler)
+	d.emit = func() (bool, bool) {
+		t, ok, err := q.poll()
+		if d.isCancelled() {
+			return false, true
+		}
+
+		if err != nil {
+			subscriber.OnError(err)
+			return false, true
+		}
+
+		if !ok {
+			subscriber.OnComplete()
+			return false, true
+		}
+
+		accepted := onNext(t)
+		if !q.isEmpty() {
+			return accepted, false
+		}
+
+		if !d.isCancelled() {
+			subscriber.OnComplete()
+		}
+		return accepted, true
+	}
+
+	sub := d.subscription()
+	subscriber.OnSubscribe(sub)
+	return sub
+}
+
+// fuse returns the FuseableFlux pulling from the queues of this one through
+// the stage.
+func (f *FuseableFlux) fuse(stage func(source *fusedQueue, errorContinue func(error, cesium.T)) *fusedQueue) cesium.Flux {
+	return newFuseableFlux(func(errorContinue func(error, cesium.T)) *fusedQueue {
+		return stage(f.queue(errorContinue), errorContinue)
+	})
+}
+
+// applyFused calls the user supplied function of a fused operator for the
+// item. If it panics, the item is passed to the OnErrorContinue strategy and
+// should be dropped, or the panic is returned as the error terminating the
+// sequence if there is no strategy.
+func applyFused(t cesium.T, f func(), errorContinue func(error, cesium.T)) (bool, error) {
+	if errorContinue == nil {
+		if err := callOperator(f, t); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	if err := callSafely(f); err != nil {
+		errorContinue(err, t)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (f *FuseableFlux) Map(mapper func(cesium.T) cesium.T) cesium.Flux {
+	if !canFuse() {
+		return FluxMapOperator(f.Flux, mapper)
+	}
+
+	return f.fuse(func(source *fusedQueue, errorContinue func(error, cesium.T)) *fusedQueue {
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				for {
+					t, ok, err := source.poll()
+					if !ok {
+						return nil, false, err
+					}
+
+					var mapped cesium.T
+					keep, err := applyFused(t, func() { mapped = mapper(t) }, errorContinue)
+					if err != nil {
+						return nil, false, err
+					}
+
+					if keep {
+						return mapped, true, nil
+					}
+				}
+			},
+			isEmpty: source.isEmpty,
+		}
+	})
+}
+
+func (f *FuseableFlux) Filter(filter func(cesium.T) bool) cesium.Flux {
+	if !canFuse() {
+		return FluxFilterOperator(f.Flux, filter)
+	}
+
+	return f.fuse(func(source *fusedQueue, errorContinue func(error, cesium.T)) *fusedQueue {
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				for {
+					t, ok, err := source.poll()
+					if !ok {
+						return nil, false, err
+					}
+
+					// A dropped item is treated as filtered out.
+					pass := false
+					if _, err := applyFused(t, func() { pass = filter(t) }, errorContinue); err != nil {
+						return nil, false, err
+					}
+
+					if pass {
+						return t, true, nil
+					}
+				}
+			},
+			isEmpty: source.isEmpty,
+		}
+	})
+}
+
+func (f *FuseableFlux) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Flux {
+	if !canFuse() {
+		return f.Flux.Handle(fn)
+	}
+
+	return f.fuse(func(source *fusedQueue, errorContinue func(error, cesium.T)) *fusedQueue {
+		done := false
+
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				for !done {
+					t, ok, err := source.poll()
+					if !ok {
+						return nil, false, err
+					}
+
+					sink := &SynchronousSink{}
+					keep, err := applyFused(t, func() { fn(t, sink) }, errorContinue)
+					if err != nil {
+						return nil, false, err
+					}
+
+					if !keep {
+						continue
+					}
+
+					sig := sink.Signal()
+					if sig == nil {
+						return nil, false, cesium.NoEmissionOnSynchronousSinkError
+					}
+
+					switch sig.Type() {
+					case cesium.SignalTypeOnNext:
+						return sig.Item(), true, nil
+					case cesium.SignalTypeOnError:
+						if errorContinue != nil {
+							errorContinue(sig.Error(), t)
+							continue
+						}
+
+						return nil, false, sig.Error()
+					default:
+						done = true
+					}
+				}
+
+				return nil, false, nil
+			},
+			isEmpty: func() bool {
+				return done || source.isEmpty()
+			},
+		}
+	})
+}
+
+func (f *FuseableFlux) DistinctUntilChanged() cesium.Flux {
+	if !canFuse() {
+		return f.Flux.DistinctUntilChanged()
+	}
+
+	return f.fuse(func(source *fusedQueue, errorContinue func(error, cesium.T)) *fusedQueue {
+		started := false
+		var last cesium.T
+
+		return &fusedQueue{
+			poll: func() (cesium.T, bool, error) {
+				for {
+					t, ok, err := source.poll()
+					if !ok {
+						return nil, false, err
+					}
+
+					if started && last == t {
+						continue
+					}
+
+					started = true
+					last = t
+					return t, true, nil
+				}
+			},
+			isEmpty: source.isEmpty,
+		}
+	})
+}
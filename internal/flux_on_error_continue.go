@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"github.com/DusanKasan/cesium"
+)
+
+// errorContinuer is implemented by the subscribers that carry the
+// Flux.OnErrorContinue strategy upstream. It is looked up when subscribing, so
+// that the operators supporting it can skip the failing items instead of
+// terminating.
+type errorContinuer interface {
+	errorContinue() func(error, cesium.T)
+}
+
+// errorContinueOf returns the OnErrorContinue strategy set up downstream of
+// the subscriber, or nil if there is none.
+func errorContinueOf(s cesium.Subscriber) func(error, cesium.T) {
+	if c, ok := s.(errorContinuer); ok {
+		return c.errorContinue()
+	}
+
+	return nil
+}
+
+type errorContinueSubscriber struct {
+	cesium.Subscriber
+	fn func(error, cesium.T)
+}
+
+func (s *errorContinueSubscriber) errorContinue() func(error, cesium.T) {
+	return s.fn
+}
+
+type conditionalErrorContinueSubscriber struct {
+	*errorContinueSubscriber
+	conditional ConditionalSubscriber
+}
+
+func (s *conditionalErrorContinueSubscriber) OnNextIf(t cesium.T) bool {
+	return s.conditional.OnNextIf(t)
+}
+
+// OnErrorContinue lets the supporting upstream operators (Map, MapE, Filter
+// and Handle) recover from an error raised for an item by dropping the item,
+// passing both to the supplied function and continuing with the next one.
+func (f *Flux) OnErrorContinue(fn func(error, cesium.T)) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		s := &errorContinueSubscriber{subscriber, fn}
+		if c, ok := subscriber.(ConditionalSubscriber); ok {
+			return f.OnSubscribe(&conditionalErrorContinueSubscriber{s, c}, scheduler)
+		}
+
+		return f.OnSubscribe(s, scheduler)
+	}
+
+	return onFluxAssembly(f, &Flux{OnSubscribe: onPublish})
+}
@@ -0,0 +1,584 @@
+package internal
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// parallelPrefetch is the amount of items each stage of a ParallelFlux
+// requests in advance, the source for all of the rails and the RunOn and
+// Sequential/Ordered stages for each rail.
+const parallelPrefetch = 256
+
+type ParallelFlux struct {
+	rails int
+
+	// undecorated is set if the source was assembled by a decorator, see
+	// onFluxAssembly. The rails and the joined Flux inherit it.
+	undecorated bool
+
+	// newRails creates the rails for a single subscription. Each of them can
+	// only be subscribed to once.
+	newRails func() []cesium.Flux
+}
+
+func (f *Flux) Parallel(rails int) cesium.ParallelFlux {
+	if rails <= 0 {
+		rails = runtime.NumCPU()
+	}
+
+	undecorated := isUndecorated(f)
+
+	return &ParallelFlux{
+		rails:       rails,
+		undecorated: undecorated,
+		newRails: func() []cesium.Flux {
+			fluxes := newParallelSource(f, rails, parallelPrefetch).fluxes()
+			for _, rail := range fluxes {
+				rail.(*Flux).undecorated = undecorated
+			}
+
+			return fluxes
+		},
+	}
+}
+
+func (p *ParallelFlux) Rails() int {
+	return p.rails
+}
+
+// transform applies the operator to each of the rails.
+func (p *ParallelFlux) transform(operator func(cesium.Flux) cesium.Flux) cesium.ParallelFlux {
+	return &ParallelFlux{
+		rails:       p.rails,
+		undecorated: p.undecorated,
+		newRails: func() []cesium.Flux {
+			rails := p.newRails()
+			for i, rail := range rails {
+				rails[i] = operator(rail)
+			}
+
+			return rails
+		},
+	}
+}
+
+func (p *ParallelFlux) RunOn(scheduler cesium.Scheduler) cesium.ParallelFlux {
+	return p.transform(func(rail cesium.Flux) cesium.Flux {
+		return publishOn(rail, scheduler, parallelPrefetch, parallelPrefetch-parallelPrefetch>>2)
+	})
+}
+
+func (p *ParallelFlux) Map(fn func(cesium.T) cesium.T) cesium.ParallelFlux {
+	return p.transform(func(rail cesium.Flux) cesium.Flux {
+		return rail.Map(fn)
+	})
+}
+
+func (p *ParallelFlux) Filter(fn func(cesium.T) bool) cesium.ParallelFlux {
+	return p.transform(func(rail cesium.Flux) cesium.Flux {
+		return rail.Filter(fn)
+	})
+}
+
+func (p *ParallelFlux) FlatMap(fn func(cesium.T) cesium.Publisher) cesium.ParallelFlux {
+	return p.transform(func(rail cesium.Flux) cesium.Flux {
+		return rail.FlatMap(fn)
+	})
+}
+
+func (p *ParallelFlux) Reduce(fn func(cesium.T, cesium.T) cesium.T) cesium.Mono {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		var reduced []cesium.Publisher
+		for _, rail := range p.newRails() {
+			reduced = append(reduced, rail.Reduce(fn))
+		}
+
+		return joinRails(reduced, nil).Reduce(fn).Subscribe(subscriber)
+	}
+
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish, undecorated: p.undecorated})
+}
+
+func (p *ParallelFlux) Sequential() cesium.Flux {
+	return p.join(nil)
+}
+
+func (p *ParallelFlux) Ordered(less func(cesium.T, cesium.T) bool) cesium.Flux {
+	return p.join(less)
+}
+
+func (p *ParallelFlux) join(less func(cesium.T, cesium.T) bool) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		var rails []cesium.Publisher
+		for _, rail := range p.newRails() {
+			rails = append(rails, rail)
+		}
+
+		return joinRails(rails, less).Subscribe(subscriber)
+	}
+
+	return onFluxAssembly(nil, &Flux{OnSubscribe: onPublish, undecorated: p.undecorated})
+}
+
+// parallelSource distributes the items of the source to the rails
+// round-robin, into a queue per rail. It subscribes to the source once all of
+// the rails are subscribed to and requests the prefetched amount of items,
+// replenishing them as the rails consume them.
+type parallelSource struct {
+	source   cesium.Publisher
+	prefetch int64
+
+	mux          sync.Mutex
+	rails        []*connectionSubscriber
+	queues       [][]cesium.T
+	terminated   []bool
+	subscribed   int
+	next         int
+	consumed     int64
+	subscription cesium.Subscription
+	cancelled    bool
+	done         bool
+	err          error
+	draining     bool
+	missed       bool
+}
+
+func newParallelSource(source cesium.Publisher, rails int, prefetch int64) *parallelSource {
+	return &parallelSource{
+		source:     source,
+		prefetch:   prefetch,
+		rails:      make([]*connectionSubscriber, rails),
+		queues:     make([][]cesium.T, rails),
+		terminated: make([]bool, rails),
+	}
+}
+
+// fluxes returns the rails, each can be subscribed to once.
+func (c *parallelSource) fluxes() []cesium.Flux {
+	fluxes := make([]cesium.Flux, len(c.rails))
+	for i := range c.rails {
+		index := i
+		fluxes[i] = &Flux{OnSubscribe: func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+			return c.subscribe(index, subscriber)
+		}}
+	}
+
+	return fluxes
+}
+
+func (c *parallelSource) subscribe(index int, subscriber cesium.Subscriber) cesium.Subscription {
+	s := &connectionSubscriber{subscriber: subscriber}
+	sub := &Subscription{
+		CancelFunc:  s.cancel,
+		RequestFunc: s.request,
+	}
+
+	subscriber.OnSubscribe(sub)
+
+	c.mux.Lock()
+	if c.rails[index] != nil {
+		c.mux.Unlock()
+		s.fail(cesium.SingleSubscriberError)
+		return sub
+	}
+
+	c.rails[index] = s
+	s.setConnection(c)
+	c.subscribed++
+	connect := c.subscribed == len(c.rails)
+	c.mux.Unlock()
+
+	if connect {
+		subscription := c.source.Subscribe(DoObserver(c.onNext, c.onComplete, c.onError))
+
+		c.mux.Lock()
+		c.subscription = subscription
+		cancelled := c.cancelled
+		c.mux.Unlock()
+
+		if cancelled {
+			subscription.Cancel()
+		} else {
+			subscription.Request(c.prefetch)
+		}
+	}
+
+	return sub
+}
+
+func (c *parallelSource) onNext(t cesium.T) {
+	c.mux.Lock()
+	if c.done {
+		c.mux.Unlock()
+		return
+	}
+
+	// Skip the cancelled rails, the item is dropped if all of them are.
+	for i := 0; i < len(c.rails); i++ {
+		index := c.next
+		c.next = (c.next + 1) % len(c.rails)
+
+		if !c.rails[index].isCancelled() {
+			c.queues[index] = append(c.queues[index], t)
+			break
+		}
+	}
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *parallelSource) onComplete() {
+	c.mux.Lock()
+	c.done = true
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *parallelSource) onError(err error) {
+	c.mux.Lock()
+	if !c.done {
+		c.done = true
+		c.err = err
+	}
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *parallelSource) drain() {
+	c.mux.Lock()
+	if c.draining {
+		c.missed = true
+		c.mux.Unlock()
+		return
+	}
+	c.draining = true
+
+	for {
+		c.missed = false
+
+		// Not all of the rails are subscribed to yet.
+		if c.subscribed < len(c.rails) {
+			c.draining = false
+			c.mux.Unlock()
+			return
+		}
+
+		cancelled := 0
+		for i, s := range c.rails {
+			if s.isCancelled() {
+				c.consumed += int64(len(c.queues[i]))
+				c.queues[i] = nil
+				cancelled++
+				continue
+			}
+
+			// An error is emitted to the rails right away, dropping the
+			// queued items.
+			for c.err == nil && len(c.queues[i]) > 0 && s.demand() > 0 {
+				item := c.queues[i][0]
+				c.queues[i] = c.queues[i][1:]
+				c.consumed++
+				c.mux.Unlock()
+
+				s.emit(item)
+
+				c.mux.Lock()
+			}
+
+			if c.done && (c.err != nil || len(c.queues[i]) == 0) && !c.terminated[i] {
+				c.terminated[i] = true
+				c.queues[i] = nil
+				err := c.err
+				c.mux.Unlock()
+
+				s.terminate(err)
+
+				c.mux.Lock()
+			}
+		}
+
+		replenish := int64(0)
+		if c.consumed >= c.prefetch-c.prefetch>>2 {
+			replenish = c.consumed
+			c.consumed = 0
+		}
+
+		cancel := cancelled == len(c.rails) && !c.cancelled
+		if cancel {
+			c.cancelled = true
+		}
+
+		subscription := c.subscription
+		if !c.done && subscription != nil && (cancel || replenish > 0) {
+			c.mux.Unlock()
+
+			if cancel {
+				subscription.Cancel()
+			} else {
+				subscription.Request(replenish)
+			}
+
+			c.mux.Lock()
+		}
+
+		if !c.missed {
+			c.draining = false
+			c.mux.Unlock()
+			return
+		}
+	}
+}
+
+// joinRails returns a Flux that merges the rails. If less is nil, the items
+// are emitted as they come, otherwise the least of the first items of the
+// rails is emitted once each of the rails has an item or has completed. Each
+// rail is requested in advance, replenishing the items as they are emitted.
+// It is assembled upon subscription, by the operators already decorated, so
+// it is not decorated itself.
+func joinRails(rails []cesium.Publisher, less func(cesium.T, cesium.T) bool) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		j := &parallelJoin{subscriber: subscriber, less: less, prefetch: parallelPrefetch}
+		for range rails {
+			j.rails = append(j.rails, &joinedRail{join: j})
+		}
+
+		sub := &Subscription{
+			CancelFunc:  j.cancel,
+			RequestFunc: j.request,
+		}
+
+		subscriber.OnSubscribe(sub)
+		for i, rail := range rails {
+			j.rails[i].setSubscription(rail.Subscribe(j.rails[i]))
+		}
+
+		return sub
+	}
+
+	return &Flux{OnSubscribe: onPublish, undecorated: true}
+}
+
+type parallelJoin struct {
+	requested  int64 // first, to be aligned for the atomic operations
+	subscriber cesium.Subscriber
+	less       func(cesium.T, cesium.T) bool
+	prefetch   int64
+
+	mux        sync.Mutex
+	rails      []*joinedRail
+	next       int
+	cancelled  bool
+	err        error
+	terminated bool
+	draining   bool
+	missed     bool
+}
+
+// joinedRail is the subscriber of a rail merged by the parallelJoin. Its
+// fields are guarded by the mutex of the parallelJoin.
+type joinedRail struct {
+	join         *parallelJoin
+	subscription cesium.Subscription
+	queue        []cesium.T
+	consumed     int64
+	done         bool
+}
+
+func (r *joinedRail) setSubscription(subscription cesium.Subscription) {
+	if subscription == nil {
+		return
+	}
+
+	r.join.mux.Lock()
+	if r.subscription != nil {
+		r.join.mux.Unlock()
+		return
+	}
+	r.subscription = subscription
+	cancelled := r.join.cancelled
+	r.join.mux.Unlock()
+
+	if cancelled {
+		subscription.Cancel()
+	} else {
+		subscription.Request(r.join.prefetch)
+	}
+}
+
+func (r *joinedRail) OnSubscribe(subscription cesium.Subscription) {
+	r.setSubscription(subscription)
+}
+
+func (r *joinedRail) OnNext(t cesium.T) {
+	r.join.mux.Lock()
+	r.queue = append(r.queue, t)
+	r.join.mux.Unlock()
+	r.join.drain()
+}
+
+func (r *joinedRail) OnComplete() {
+	r.join.mux.Lock()
+	r.done = true
+	r.join.mux.Unlock()
+	r.join.drain()
+}
+
+func (r *joinedRail) OnError(err error) {
+	r.join.mux.Lock()
+	r.done = true
+	if r.join.err == nil {
+		r.join.err = err
+	}
+	r.join.mux.Unlock()
+	r.join.drain()
+}
+
+func (j *parallelJoin) request(n int64) {
+	addDemandAtomic(&j.requested, n)
+	j.drain()
+}
+
+func (j *parallelJoin) cancel() {
+	j.mux.Lock()
+	if j.cancelled {
+		j.mux.Unlock()
+		return
+	}
+	j.cancelled = true
+	j.mux.Unlock()
+
+	j.cancelRails()
+}
+
+func (j *parallelJoin) cancelRails() {
+	j.mux.Lock()
+	var subscriptions []cesium.Subscription
+	for _, r := range j.rails {
+		r.queue = nil
+		if r.subscription != nil {
+			subscriptions = append(subscriptions, r.subscription)
+		}
+	}
+	j.mux.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.Cancel()
+	}
+}
+
+// pick returns the index of the rail to emit the next item from, or -1 if
+// there is no item that can be emitted yet.
+func (j *parallelJoin) pick() int {
+	if j.less == nil {
+		for i := 0; i < len(j.rails); i++ {
+			index := (j.next + i) % len(j.rails)
+			if len(j.rails[index].queue) > 0 {
+				j.next = (index + 1) % len(j.rails)
+				return index
+			}
+		}
+
+		return -1
+	}
+
+	min := -1
+	for i, r := range j.rails {
+		if len(r.queue) == 0 {
+			if !r.done {
+				return -1
+			}
+			continue
+		}
+
+		if min < 0 || j.less(r.queue[0], j.rails[min].queue[0]) {
+			min = i
+		}
+	}
+
+	return min
+}
+
+func (j *parallelJoin) drain() {
+	j.mux.Lock()
+	if j.draining {
+		j.missed = true
+		j.mux.Unlock()
+		return
+	}
+	j.draining = true
+
+	for {
+		j.missed = false
+
+		if j.cancelled || j.terminated {
+			j.draining = false
+			j.mux.Unlock()
+			return
+		}
+
+		if j.err != nil {
+			j.terminated = true
+			err := j.err
+			j.mux.Unlock()
+
+			j.cancelRails()
+			j.subscriber.OnError(err)
+			return
+		}
+
+		for atomic.LoadInt64(&j.requested) > 0 && !j.cancelled && j.err == nil {
+			index := j.pick()
+			if index < 0 {
+				break
+			}
+
+			r := j.rails[index]
+			item := r.queue[0]
+			r.queue = r.queue[1:]
+			produceDemand(&j.requested, 1)
+
+			replenish := int64(0)
+			r.consumed++
+			if r.consumed >= j.prefetch-j.prefetch>>2 {
+				replenish = r.consumed
+				r.consumed = 0
+			}
+			subscription := r.subscription
+			j.mux.Unlock()
+
+			j.subscriber.OnNext(item)
+
+			if replenish > 0 && subscription != nil {
+				subscription.Request(replenish)
+			}
+
+			j.mux.Lock()
+		}
+
+		finished := j.err == nil && !j.cancelled
+		for _, r := range j.rails {
+			if !r.done || len(r.queue) > 0 {
+				finished = false
+			}
+		}
+
+		if finished {
+			j.terminated = true
+			j.mux.Unlock()
+
+			j.subscriber.OnComplete()
+			return
+		}
+
+		// The error is emitted in the next iteration.
+		if !j.missed && j.err == nil {
+			j.draining = false
+			j.mux.Unlock()
+			return
+		}
+	}
+}
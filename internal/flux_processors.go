@@ -0,0 +1,317 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// processorConnection delivers the signals received by a fluxProcessor to its
+// subscribers.
+type processorConnection interface {
+	drainer
+	add(*connectionSubscriber) bool
+	setSubscription(cesium.Subscription)
+	next(cesium.T)
+	tryNext(cesium.T) cesium.EmitResult
+	complete()
+	error(error)
+}
+
+// fluxProcessor is a cesium.FluxProcessor that multicasts the signals it
+// receives to its subscribers through the connection.
+type fluxProcessor struct {
+	*Flux
+	connection     processorConnection
+	maxSubscribers int
+
+	mux         sync.Mutex
+	subscribers int
+	first       *connectionSubscriber
+	done        bool
+	err         error
+}
+
+func newFluxProcessor(c processorConnection, maxSubscribers int) *fluxProcessor {
+	p := &fluxProcessor{connection: c, maxSubscribers: maxSubscribers}
+	p.Flux = &Flux{OnSubscribe: p.subscribe}
+
+	return p
+}
+
+func (p *fluxProcessor) subscribe(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+	s := &connectionSubscriber{subscriber: subscriber}
+	sub := &Subscription{
+		CancelFunc:  s.cancel,
+		RequestFunc: s.request,
+	}
+
+	subscriber.OnSubscribe(sub)
+
+	p.mux.Lock()
+	if p.maxSubscribers > 0 && p.subscribers >= p.maxSubscribers {
+		p.mux.Unlock()
+		s.fail(cesium.SingleSubscriberError)
+		return sub
+	}
+	p.subscribers++
+	if p.first == nil {
+		p.first = s
+	}
+	p.mux.Unlock()
+
+	if !p.connection.add(s) {
+		// The processor has already terminated.
+		p.mux.Lock()
+		err := p.err
+		p.mux.Unlock()
+
+		s.terminate(err)
+	}
+
+	return sub
+}
+
+func (p *fluxProcessor) OnSubscribe(subscription cesium.Subscription) {
+	if subscription == nil {
+		return
+	}
+
+	p.connection.setSubscription(subscription)
+}
+
+func (p *fluxProcessor) OnNext(t cesium.T) {
+	p.mux.Lock()
+	done := p.done
+	p.mux.Unlock()
+
+	if done {
+		onNextDropped(t)
+		return
+	}
+
+	p.connection.next(t)
+}
+
+func (p *fluxProcessor) OnComplete() {
+	p.tryComplete()
+}
+
+func (p *fluxProcessor) OnError(err error) {
+	if p.tryError(err) == cesium.EmitResultFailTerminated {
+		onErrorDropped(err)
+	}
+}
+
+// tryNext emits the item unless the processor has terminated, its only
+// subscriber has cancelled or its buffer is full.
+func (p *fluxProcessor) tryNext(t cesium.T) cesium.EmitResult {
+	p.mux.Lock()
+	done := p.done
+	cancelled := p.maxSubscribers == 1 && p.first != nil && p.first.isCancelled()
+	p.mux.Unlock()
+
+	if done {
+		return cesium.EmitResultFailTerminated
+	}
+
+	if cancelled {
+		return cesium.EmitResultFailCancelled
+	}
+
+	return p.connection.tryNext(t)
+}
+
+func (p *fluxProcessor) tryComplete() cesium.EmitResult {
+	p.mux.Lock()
+	if p.done {
+		p.mux.Unlock()
+		return cesium.EmitResultFailTerminated
+	}
+	p.done = true
+	p.mux.Unlock()
+
+	p.connection.complete()
+	return cesium.EmitResultOK
+}
+
+func (p *fluxProcessor) tryError(err error) cesium.EmitResult {
+	p.mux.Lock()
+	if p.done {
+		p.mux.Unlock()
+		return cesium.EmitResultFailTerminated
+	}
+	p.done = true
+	p.err = err
+	p.mux.Unlock()
+
+	p.connection.error(err)
+	return cesium.EmitResultOK
+}
+
+// directConnection emits the items to the subscribers as they come, without
+// buffering them. The subscribers without demand for an item are terminated
+// with cesium.DownstreamUnableToKeepUpError.
+type directConnection struct {
+	mux          sync.Mutex
+	subscribers  []*connectionSubscriber
+	pending      []cesium.T
+	subscription cesium.Subscription
+	done         bool
+	err          error
+	terminated   bool
+	draining     bool
+	missed       bool
+}
+
+func (c *directConnection) add(s *connectionSubscriber) bool {
+	c.mux.Lock()
+	if c.terminated {
+		c.mux.Unlock()
+		return false
+	}
+
+	c.subscribers = append(c.subscribers, s)
+	s.setConnection(c)
+	c.mux.Unlock()
+
+	c.drain()
+	return true
+}
+
+func (c *directConnection) setSubscription(subscription cesium.Subscription) {
+	c.mux.Lock()
+	if c.subscription != nil {
+		c.mux.Unlock()
+		subscription.Cancel()
+		return
+	}
+
+	c.subscription = subscription
+	done := c.done
+	c.mux.Unlock()
+
+	if done {
+		subscription.Cancel()
+	} else {
+		subscription.RequestUnbounded()
+	}
+}
+
+func (c *directConnection) next(t cesium.T) {
+	c.tryNext(t)
+}
+
+func (c *directConnection) tryNext(t cesium.T) cesium.EmitResult {
+	c.mux.Lock()
+	if c.done {
+		c.mux.Unlock()
+		return cesium.EmitResultFailTerminated
+	}
+
+	c.pending = append(c.pending, t)
+	c.mux.Unlock()
+	c.drain()
+
+	return cesium.EmitResultOK
+}
+
+func (c *directConnection) complete() {
+	c.mux.Lock()
+	c.done = true
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *directConnection) error(err error) {
+	c.mux.Lock()
+	if !c.done {
+		c.done = true
+		c.err = err
+	}
+	c.mux.Unlock()
+	c.drain()
+}
+
+func (c *directConnection) drain() {
+	c.mux.Lock()
+	if c.draining {
+		c.missed = true
+		c.mux.Unlock()
+		return
+	}
+	c.draining = true
+
+	for {
+		c.missed = false
+
+		for len(c.pending) > 0 {
+			item := c.pending[0]
+			c.pending = c.pending[1:]
+			subscribers := c.subscribers
+			c.mux.Unlock()
+
+			for _, s := range subscribers {
+				if s.demand() > 0 {
+					s.emit(item)
+				} else {
+					s.fail(cesium.DownstreamUnableToKeepUpError)
+				}
+			}
+
+			c.mux.Lock()
+		}
+
+		var subscribers []*connectionSubscriber
+		for _, s := range c.subscribers {
+			if !s.isCancelled() {
+				subscribers = append(subscribers, s)
+			}
+		}
+		c.subscribers = subscribers
+
+		if c.done && !c.terminated {
+			c.terminated = true
+			c.subscribers = nil
+			err := c.err
+			c.mux.Unlock()
+
+			for _, s := range subscribers {
+				s.terminate(err)
+			}
+
+			return
+		}
+
+		if !c.missed {
+			c.draining = false
+			c.mux.Unlock()
+			return
+		}
+	}
+}
+
+func UnicastFluxProcessor(bufferSize int) cesium.FluxProcessor {
+	if bufferSize <= 0 {
+		bufferSize = publishPrefetch
+	}
+
+	return newFluxProcessor(&publishConnection{prefetch: int64(bufferSize)}, 1)
+}
+
+func DirectFluxProcessor() cesium.FluxProcessor {
+	return newFluxProcessor(&directConnection{}, 0)
+}
+
+func EmitterFluxProcessor(bufferSize int) cesium.FluxProcessor {
+	if bufferSize <= 0 {
+		bufferSize = publishPrefetch
+	}
+
+	return newFluxProcessor(&publishConnection{prefetch: int64(bufferSize)}, 0)
+}
+
+func ReplayFluxProcessor(history int, ttl time.Duration, clock cesium.Clock) cesium.FluxProcessor {
+	return newFluxProcessor(&replayConnection{history: history, ttl: ttl, clock: clock}, 0)
+}
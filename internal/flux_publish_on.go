@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// publishOn returns a Flux that emits the items of the source on the
+// scheduler. The prefetched amount of items is requested in advance and
+// queued, replenishing the limit of items each time as many were emitted. The
+// queue is drained by a task on the scheduler which is only scheduled if there
+// is not one already running, so the items are emitted serially.
+func publishOn(source cesium.Publisher, scheduler cesium.Scheduler, prefetch int64, limit int64) cesium.Flux {
+	onPublish := func(subscriber cesium.Subscriber, s cesium.Scheduler) cesium.Subscription {
+		r := &publishOnSubscriber{subscriber: subscriber, scheduler: scheduler, prefetch: prefetch, limit: limit}
+		sub := &Subscription{
+			CancelFunc:  r.cancel,
+			RequestFunc: r.request,
+		}
+
+		subscriber.OnSubscribe(sub)
+		source.Subscribe(r)
+
+		return sub
+	}
+
+	return onFluxAssembly(source, &Flux{OnSubscribe: onPublish})
+}
+
+type publishOnSubscriber struct {
+	requested  int64 // first, to be aligned for the atomic operations
+	subscriber cesium.Subscriber
+	scheduler  cesium.Scheduler
+	prefetch   int64
+	limit      int64
+	wip        int32
+
+	mux          sync.Mutex
+	subscription cesium.Subscription
+	queue        []cesium.T
+	consumed     int64
+	cancelled    bool
+	done         bool
+	err          error
+	terminated   bool
+}
+
+func (r *publishOnSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	if subscription == nil {
+		return
+	}
+
+	r.mux.Lock()
+	if r.subscription != nil {
+		r.mux.Unlock()
+		return
+	}
+	r.subscription = subscription
+	cancelled := r.cancelled
+	r.mux.Unlock()
+
+	if cancelled {
+		subscription.Cancel()
+	} else {
+		subscription.Request(r.prefetch)
+	}
+}
+
+func (r *publishOnSubscriber) OnNext(t cesium.T) {
+	r.mux.Lock()
+	r.queue = append(r.queue, t)
+	r.mux.Unlock()
+	r.schedule()
+}
+
+func (r *publishOnSubscriber) OnComplete() {
+	r.mux.Lock()
+	r.done = true
+	r.mux.Unlock()
+	r.schedule()
+}
+
+func (r *publishOnSubscriber) OnError(err error) {
+	r.mux.Lock()
+	if !r.done {
+		r.done = true
+		r.err = err
+	}
+	r.mux.Unlock()
+	r.schedule()
+}
+
+func (r *publishOnSubscriber) request(n int64) {
+	addDemandAtomic(&r.requested, n)
+	r.schedule()
+}
+
+func (r *publishOnSubscriber) cancel() {
+	r.mux.Lock()
+	r.cancelled = true
+	r.queue = nil
+	subscription := r.subscription
+	r.mux.Unlock()
+
+	if subscription != nil {
+		subscription.Cancel()
+	}
+}
+
+func (r *publishOnSubscriber) schedule() {
+	if atomic.AddInt32(&r.wip, 1) == 1 {
+		r.scheduler.Schedule(func(cesium.Canceller) {
+			r.drain()
+		})
+	}
+}
+
+func (r *publishOnSubscriber) drain() {
+	missed := int32(1)
+
+	for {
+		r.mux.Lock()
+		for !r.cancelled && len(r.queue) > 0 && atomic.LoadInt64(&r.requested) > 0 {
+			item := r.queue[0]
+			r.queue = r.queue[1:]
+			produceDemand(&r.requested, 1)
+
+			replenish := int64(0)
+			r.consumed++
+			if r.consumed >= r.limit {
+				replenish = r.consumed
+				r.consumed = 0
+			}
+			subscription := r.subscription
+			r.mux.Unlock()
+
+			r.subscriber.OnNext(item)
+
+			if replenish > 0 && subscription != nil {
+				subscription.Request(replenish)
+			}
+
+			r.mux.Lock()
+		}
+
+		terminate := !r.cancelled && r.done && len(r.queue) == 0 && !r.terminated
+		if terminate {
+			r.terminated = true
+		}
+		err := r.err
+		r.mux.Unlock()
+
+		if terminate {
+			if err != nil {
+				r.subscriber.OnError(err)
+			} else {
+				r.subscriber.OnComplete()
+			}
+		}
+
+		missed = atomic.AddInt32(&r.wip, -missed)
+		if missed == 0 {
+			return
+		}
+	}
+}
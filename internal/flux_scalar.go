@@ -27,6 +27,10 @@ func (s *ScalarFlux) Map(f func(cesium.T) cesium.T) cesium.Flux {
 	return FluxMapOperator(s, f)
 }
 
+func (s *ScalarFlux) MapE(f func(cesium.T) (cesium.T, error)) cesium.Flux {
+	return FluxMapEOperator(s, f)
+}
+
 func (s *ScalarFlux) Reduce(f func(cesium.T, cesium.T) cesium.T) cesium.Mono {
 	return monoFromCallable(func() (cesium.T, bool) {
 		return s.Get()
@@ -74,7 +78,10 @@ func (s *ScalarFlux) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Fl
 	}
 
 	sink := &SynchronousSink{}
-	fn(t, sink)
+	if err := callOperator(func() { fn(t, sink) }, t); err != nil {
+		return FluxError(err)
+	}
+
 	sig := sink.Signal()
 	switch sig.Type() {
 	case cesium.SignalTypeOnNext:
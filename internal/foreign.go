@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"math"
+	"sync"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// FluxFrom adapts the publisher to cesium.Flux. The publishers not created by
+// this library are guarded at the boundary, see foreignSubscriber. A
+// FuseableFlux is adapted to the plain Flux subscribing to its whole chain.
+func FluxFrom(publisher cesium.Publisher) cesium.Flux {
+	switch p := publisher.(type) {
+	case *Flux:
+		return p
+	case *ScalarFlux:
+		return p
+	case *FuseableFlux:
+		return p.Flux
+	}
+
+	return onFluxAssembly(nil, &Flux{OnSubscribe: subscribeFunc(publisher)})
+}
+
+// MonoFrom adapts the publisher to cesium.Mono, emitting its first item and
+// cancelling it afterwards. The publishers not created by this library are
+// guarded at the boundary, see foreignSubscriber.
+func MonoFrom(publisher cesium.Publisher) cesium.Mono {
+	switch p := publisher.(type) {
+	case *Mono:
+		return p
+	case *ScalarMono:
+		return p
+	}
+
+	first := (&Flux{OnSubscribe: subscribeFunc(publisher)}).Take(1).(*Flux)
+
+	return onMonoAssembly(nil, &Mono{OnSubscribe: first.OnSubscribe})
+}
+
+// fromPublisher returns the function subscribing to a publisher not created by
+// this library through a foreignSubscriber.
+func fromPublisher(publisher cesium.Publisher) func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription {
+	return func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		if scheduler == nil {
+			scheduler = SeparateGoroutineScheduler()
+		}
+
+		s := &foreignSubscriber{subscriber: subscriber, scheduler: scheduler}
+		sub := &Subscription{
+			CancelFunc:  s.cancel,
+			RequestFunc: s.request,
+		}
+
+		subscriber.OnSubscribe(sub)
+		if err := callSafely(func() { publisher.Subscribe(s) }); err != nil {
+			s.OnError(err)
+		}
+
+		return sub
+	}
+}
+
+// foreignSubscriber guards the subscriber against a publisher not created by
+// this library. The subscriber receives its subscription before the publisher
+// is subscribed to and the signals are delivered serially, even if the
+// publisher signals concurrently or from within Request. The requests and the
+// cancellation are passed to the publisher serially on the scheduler, so that
+// the items it emits from within Request never reach a subscriber that is
+// still requesting them. Only the first subscription is kept and an item
+// signalled without demand cancels the publisher, terminating the subscriber
+// with cesium.UnrequestedItemError (rule 1.1). The panics of the publisher are
+// emitted as cesium.PanicError.
+type foreignSubscriber struct {
+	subscriber cesium.Subscriber
+	scheduler  cesium.Scheduler
+
+	mux        sync.Mutex
+	upstream   cesium.Subscription
+	demand     int64
+	requested  int64
+	requesting bool
+	cancelled  bool
+	disposed   bool
+	done       bool
+	draining   bool
+	queue      []cesium.Signal
+}
+
+func (s *foreignSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	if subscription == nil {
+		return
+	}
+
+	s.mux.Lock()
+	if s.upstream != nil {
+		s.mux.Unlock()
+		callSafely(subscription.Cancel)
+		return
+	}
+
+	s.upstream = subscription
+	s.callUpstream()
+}
+
+func (s *foreignSubscriber) OnNext(t cesium.T) {
+	s.mux.Lock()
+	if s.done || s.cancelled {
+		s.mux.Unlock()
+		onNextDropped(t)
+		return
+	}
+
+	if s.demand == 0 {
+		s.mux.Unlock()
+		onNextDropped(t)
+		s.fail(cesium.UnrequestedItemError)
+		return
+	}
+
+	if s.demand != math.MaxInt64 {
+		s.demand--
+	}
+	s.queue = append(s.queue, NextSignal(t))
+	s.mux.Unlock()
+
+	s.drain()
+}
+
+func (s *foreignSubscriber) OnComplete() {
+	s.terminate(CompleteSignal())
+}
+
+func (s *foreignSubscriber) OnError(err error) {
+	if !s.terminate(ErrorSignal(err)) {
+		onErrorDropped(err)
+	}
+}
+
+// terminate queues the terminal signal unless the subscriber is already
+// terminated.
+func (s *foreignSubscriber) terminate(signal cesium.Signal) bool {
+	s.mux.Lock()
+	if s.done || s.cancelled {
+		s.mux.Unlock()
+		return false
+	}
+
+	s.done = true
+	s.queue = append(s.queue, signal)
+	s.mux.Unlock()
+
+	s.drain()
+	return true
+}
+
+// fail cancels the publisher and terminates the subscriber with the error.
+func (s *foreignSubscriber) fail(err error) {
+	if !s.terminate(ErrorSignal(err)) {
+		onErrorDropped(err)
+	}
+
+	s.mux.Lock()
+	s.disposed = true
+	s.callUpstream()
+}
+
+// drain delivers the queued signals, unless they are being delivered already
+// further up the stack or by another goroutine.
+func (s *foreignSubscriber) drain() {
+	s.mux.Lock()
+	if s.draining {
+		s.mux.Unlock()
+		return
+	}
+	s.draining = true
+
+	for len(s.queue) > 0 && !s.cancelled {
+		signal := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mux.Unlock()
+
+		signal.Accept(s.subscriber)
+
+		s.mux.Lock()
+	}
+
+	s.queue = nil
+	s.draining = false
+	s.mux.Unlock()
+}
+
+// callUpstream schedules passing the outstanding requests and the
+// cancellation to the publisher. It is called with the mutex locked and
+// unlocks it. Only one call is made to the publisher at a time, the requests
+// made meanwhile, like the ones made from OnNext within its Request, are
+// passed on by the loop already running.
+func (s *foreignSubscriber) callUpstream() {
+	if s.requesting || s.upstream == nil {
+		s.mux.Unlock()
+		return
+	}
+	s.requesting = true
+	s.mux.Unlock()
+
+	s.scheduler.Schedule(func(cesium.Canceller) {
+		s.mux.Lock()
+		upstream := s.upstream
+		for {
+			if s.disposed {
+				s.requested = 0
+				s.mux.Unlock()
+				callSafely(upstream.Cancel)
+				return
+			}
+
+			if s.requested == 0 {
+				break
+			}
+
+			n := s.requested
+			s.requested = 0
+			s.mux.Unlock()
+
+			if err := callSafely(func() { upstream.Request(n) }); err != nil {
+				s.fail(err)
+			}
+
+			s.mux.Lock()
+		}
+
+		s.requesting = false
+		s.mux.Unlock()
+	})
+}
+
+func (s *foreignSubscriber) request(n int64) {
+	s.mux.Lock()
+	if s.disposed || s.done {
+		s.mux.Unlock()
+		return
+	}
+
+	s.demand = addDemand(s.demand, n)
+	s.requested = addDemand(s.requested, n)
+	s.callUpstream()
+}
+
+func (s *foreignSubscriber) cancel() {
+	s.mux.Lock()
+	if s.disposed {
+		s.mux.Unlock()
+		return
+	}
+
+	s.cancelled = true
+	s.disposed = true
+	s.callUpstream()
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"sync"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// hooks holds the global callbacks set up through the hooks package.
+var hooks = struct {
+	mux           sync.RWMutex
+	nextDropped   func(cesium.T)
+	errorDropped  func(error)
+	operatorError func(error, cesium.T) error
+	decoratorKeys []string
+	decorators    map[string]func(cesium.Publisher) cesium.Publisher
+}{decorators: map[string]func(cesium.Publisher) cesium.Publisher{}}
+
+// SetOnNextDropped sets the function called with the items that are dropped,
+// like the ones emitted after a terminal signal or without a demand. Passing
+// nil removes it.
+func SetOnNextDropped(f func(cesium.T)) {
+	hooks.mux.Lock()
+	hooks.nextDropped = f
+	hooks.mux.Unlock()
+}
+
+// SetOnErrorDropped sets the function called with the errors that can not be
+// emitted, like the ones emitted after a terminal signal. Passing nil removes
+// it.
+func SetOnErrorDropped(f func(error)) {
+	hooks.mux.Lock()
+	hooks.errorDropped = f
+	hooks.mux.Unlock()
+}
+
+// SetOnOperatorError sets the function that maps the errors raised by the user
+// supplied functions of the operators before they are emitted, along with the
+// item being processed if there is one. Passing nil removes it.
+func SetOnOperatorError(f func(error, cesium.T) error) {
+	hooks.mux.Lock()
+	hooks.operatorError = f
+	hooks.mux.Unlock()
+}
+
+// SetOnEachOperator sets the function decorating each operator assembled from
+// now on under the key, replacing the previous one set under it. The
+// decorators are applied in the order their keys were first set. Passing nil
+// removes it.
+func SetOnEachOperator(key string, f func(cesium.Publisher) cesium.Publisher) {
+	hooks.mux.Lock()
+	defer hooks.mux.Unlock()
+
+	if f == nil {
+		if _, ok := hooks.decorators[key]; ok {
+			delete(hooks.decorators, key)
+			for i, k := range hooks.decoratorKeys {
+				if k == key {
+					hooks.decoratorKeys = append(hooks.decoratorKeys[:i:i], hooks.decoratorKeys[i+1:]...)
+					break
+				}
+			}
+		}
+
+		return
+	}
+
+	if _, ok := hooks.decorators[key]; !ok {
+		hooks.decoratorKeys = append(hooks.decoratorKeys, key)
+	}
+	hooks.decorators[key] = f
+}
+
+// ResetHooks removes all the hooks.
+func ResetHooks() {
+	hooks.mux.Lock()
+	hooks.nextDropped = nil
+	hooks.errorDropped = nil
+	hooks.operatorError = nil
+	hooks.decoratorKeys = nil
+	hooks.decorators = map[string]func(cesium.Publisher) cesium.Publisher{}
+	hooks.mux.Unlock()
+}
+
+func onNextDropped(t cesium.T) {
+	hooks.mux.RLock()
+	f := hooks.nextDropped
+	hooks.mux.RUnlock()
+
+	if f != nil {
+		f(t)
+	}
+}
+
+func onErrorDropped(err error) {
+	hooks.mux.RLock()
+	f := hooks.errorDropped
+	hooks.mux.RUnlock()
+
+	if f != nil {
+		f(err)
+	}
+}
+
+func onOperatorError(err error, t cesium.T) error {
+	hooks.mux.RLock()
+	f := hooks.operatorError
+	hooks.mux.RUnlock()
+
+	if f == nil {
+		return err
+	}
+
+	if mapped := f(err, t); mapped != nil {
+		return mapped
+	}
+
+	return err
+}
+
+func operatorDecorators() []func(cesium.Publisher) cesium.Publisher {
+	hooks.mux.RLock()
+	defer hooks.mux.RUnlock()
+
+	if len(hooks.decoratorKeys) == 0 {
+		return nil
+	}
+
+	decorators := make([]func(cesium.Publisher) cesium.Publisher, len(hooks.decoratorKeys))
+	for i, key := range hooks.decoratorKeys {
+		decorators[i] = hooks.decorators[key]
+	}
+
+	return decorators
+}
+
+// decorate applies the decorators set up through SetOnEachOperator to the
+// operator.
+func decorate(decorators []func(cesium.Publisher) cesium.Publisher, operator cesium.Publisher) cesium.Publisher {
+	for _, decorator := range decorators {
+		operator = decorator(operator)
+	}
+
+	return operator
+}
+
+// subscribeFunc returns the function subscribing to the publisher. The
+// publishers not created by this library are subscribed to through a
+// foreignSubscriber.
+func subscribeFunc(publisher cesium.Publisher) func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription {
+	switch p := publisher.(type) {
+	case *Flux:
+		return p.OnSubscribe
+	case *Mono:
+		return p.OnSubscribe
+	case *ScalarFlux:
+		return subscribeFunc(p.Flux)
+	case *FuseableFlux:
+		return p.OnSubscribe
+	case *ScalarMono:
+		return subscribeFunc(p.Mono)
+	default:
+		return fromPublisher(publisher)
+	}
+}
@@ -10,10 +10,14 @@ import (
 
 type Mono struct {
 	OnSubscribe func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription
+
+	// undecorated is set on the operators passed to the decorators and the
+	// ones assembled from them, see onMonoAssembly.
+	undecorated bool
 }
 
 func (m *Mono) Subscribe(subscriber cesium.Subscriber) cesium.Subscription {
-	return m.OnSubscribe(subscriber, nil)
+	return subscribeSpec(m.OnSubscribe, subscriber)
 }
 
 func (m *Mono) Filter(filter func(t cesium.T) bool) cesium.Mono {
@@ -24,6 +28,28 @@ func (m *Mono) Map(mapper func(t cesium.T) cesium.T) cesium.Mono {
 	return MonoMapOperator(m, mapper)
 }
 
+func (m *Mono) MapE(mapper func(t cesium.T) (cesium.T, error)) cesium.Mono {
+	return MonoMapEOperator(m, mapper)
+}
+
+func (m *Mono) Timestamp(scheduler ...cesium.TimedScheduler) cesium.Mono {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return MonoTimestampOperator(m, sch)
+}
+
+func (m *Mono) Elapsed(scheduler ...cesium.TimedScheduler) cesium.Mono {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return MonoElapsedOperator(m, sch)
+}
+
 func (m *Mono) DoFinally(fn func()) cesium.Mono {
 	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
 		p := DoFinallyProcessor(fn)
@@ -45,7 +71,7 @@ func (m *Mono) DoFinally(fn func()) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) Log(logger *log.Logger) cesium.Mono {
@@ -87,7 +113,7 @@ func (m *Mono) Log(logger *log.Logger) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnNext(fn func(cesium.T)) cesium.Mono {
@@ -118,7 +144,7 @@ func (m *Mono) DoOnNext(fn func(cesium.T)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnError(fn func(error)) cesium.Mono {
@@ -151,7 +177,7 @@ func (m *Mono) DoOnError(fn func(error)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnCancel(fn func()) cesium.Mono {
@@ -184,7 +210,7 @@ func (m *Mono) DoOnCancel(fn func()) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnSubscribe(fn func(cesium.Subscription)) cesium.Mono {
@@ -217,7 +243,7 @@ func (m *Mono) DoOnSubscribe(fn func(cesium.Subscription)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnRequest(fn func(int64)) cesium.Mono {
@@ -250,7 +276,7 @@ func (m *Mono) DoOnRequest(fn func(int64)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnTerminate(fn func()) cesium.Mono {
@@ -285,7 +311,7 @@ func (m *Mono) DoOnTerminate(fn func()) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnSuccess(fn func()) cesium.Mono {
@@ -318,7 +344,7 @@ func (m *Mono) DoOnSuccess(fn func()) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoAfterTerminate(fn func()) cesium.Mono {
@@ -342,7 +368,7 @@ func (m *Mono) DoAfterTerminate(fn func()) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) ConcatWith(publishers ...cesium.Publisher) cesium.Flux {
@@ -371,7 +397,7 @@ func (m *Mono) ConcatWith(publishers ...cesium.Publisher) cesium.Flux {
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(m, &Flux{OnSubscribe: onPublish})
 }
 
 func (m *Mono) FlatMap(fn func(cesium.T) cesium.Mono, scheduler ...cesium.Scheduler) cesium.Mono {
@@ -400,7 +426,7 @@ func (m *Mono) FlatMap(fn func(cesium.T) cesium.Mono, scheduler ...cesium.Schedu
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Mono {
@@ -424,7 +450,7 @@ func (m *Mono) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) FlatMapMany(fn func(cesium.T) cesium.Publisher, scheduler ...cesium.Scheduler) cesium.Flux {
@@ -453,7 +479,7 @@ func (m *Mono) FlatMapMany(fn func(cesium.T) cesium.Publisher, scheduler ...cesi
 		return sub
 	}
 
-	return &Flux{onPublish}
+	return onFluxAssembly(m, &Flux{OnSubscribe: onPublish})
 }
 
 func (m *Mono) Block() (cesium.T, bool, error) {
@@ -537,7 +563,7 @@ func (m *Mono) OnErrorReturn(fallbackValue cesium.T) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) DoOnEach(fn func(cesium.Signal)) cesium.Mono {
@@ -560,7 +586,7 @@ func (m *Mono) DoOnEach(fn func(cesium.Signal)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) Materialize() cesium.Mono {
@@ -583,7 +609,7 @@ func (m *Mono) Materialize() cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) Dematerialize() cesium.Mono {
@@ -606,7 +632,7 @@ func (m *Mono) Dematerialize() cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) ToChannel() (<-chan cesium.T, <-chan error) {
@@ -655,7 +681,7 @@ func (m *Mono) OnErrorResume(predicate func(error) bool, publisher cesium.Mono)
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
 }
 
 func (m *Mono) OnErrorMap(mapper func(error) error) cesium.Mono {
@@ -678,5 +704,120 @@ func (m *Mono) OnErrorMap(mapper func(error) error) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{onPublish}
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
+}
+
+func (m *Mono) FilterWhen(fn func(cesium.T) cesium.Publisher) cesium.Mono {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := FilterWhenProcessor(fn, 1)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := m.OnSubscribe(p, scheduler)
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
+}
+
+func (m *Mono) DelayElement(delay time.Duration, scheduler ...cesium.TimedScheduler) cesium.Mono {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	onPublish := func(subscriber cesium.Subscriber, s cesium.Scheduler) cesium.Subscription {
+		p := DelayElementsProcessor(delay, sch)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := m.OnSubscribe(p, s)
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
+}
+
+func (m *Mono) DelayUntil(fn func(cesium.T) cesium.Publisher) cesium.Mono {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := DelayUntilProcessor(fn)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := m.OnSubscribe(p, scheduler)
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
+}
+
+func (m *Mono) Cache() cesium.Mono {
+	return m.cache(0, TimerScheduler())
+}
+
+func (m *Mono) CacheWithTTL(ttl time.Duration, scheduler ...cesium.TimedScheduler) cesium.Mono {
+	var sch = TimerScheduler()
+	if len(scheduler) > 0 {
+		sch = scheduler[0]
+	}
+
+	return m.cache(ttl, sch)
+}
+
+func (m *Mono) cache(ttl time.Duration, clock cesium.Clock) cesium.Mono {
+	cf := newConnectableFlux(func() connection {
+		return &replayConnection{
+			source:  m,
+			history: 1,
+			ttl:     ttl,
+			clock:   clock,
+			expires: true,
+		}
+	})
+
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		sub := cf.subscribe(subscriber, scheduler)
+
+		// Once the cached result expires, this connects to the source again.
+		cf.Connect()
+		return sub
+	}
+
+	return onMonoAssembly(m, &Mono{OnSubscribe: onPublish})
+}
+
+func (m *Mono) Share() cesium.Mono {
+	cf := (&Flux{OnSubscribe: m.OnSubscribe}).Publish().(*ConnectableFlux)
+
+	return onMonoAssembly(m, &Mono{OnSubscribe: cf.refCount(1, 0)})
 }
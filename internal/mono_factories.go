@@ -23,8 +23,15 @@ func MonoJustOrEmpty(t cesium.T) cesium.Mono {
 	})
 }
 
+// monoFromCallable is internal way to instantiate a ScalarMono. The function
+// is called once, upon assembly, and a panic in it is emitted as an error to
+// every subscriber.
 func monoFromCallable(f func() (cesium.T, bool)) cesium.Mono {
-	t, ok := f()
+	var t cesium.T
+	var ok bool
+	if err := callOperator(func() { t, ok = f() }, nil); err != nil {
+		return MonoError(err)
+	}
 
 	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
 		if scheduler == nil {
@@ -81,6 +88,69 @@ func MonoFromCallable(f func() cesium.T) cesium.Mono {
 	})
 }
 
+// FromSupplier creates new cesium.Mono that emits the item returned from the
+// supplied function, or the error if it returns one. The function is called
+// for each subscription. If it returns nil, the returned Mono completes empty.
+func MonoFromSupplier(f func() (cesium.T, error)) cesium.Mono {
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		if scheduler == nil {
+			scheduler = SeparateGoroutineScheduler()
+		}
+
+		mux := sync.Mutex{}
+		cancelled := false
+		planned := false
+		var cancellable cesium.Cancellable
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				mux.Lock()
+				cancelled = true
+				if cancellable != nil {
+					cancellable.Cancel()
+				}
+				mux.Unlock()
+			},
+			RequestFunc: func(n int64) {
+				mux.Lock()
+				if cancelled || planned {
+					mux.Unlock()
+					return
+				}
+
+				cancellable = scheduler.Schedule(func(canceller cesium.Canceller) {
+					var t cesium.T
+					var err error
+					if perr := callOperator(func() { t, err = f() }, nil); perr != nil {
+						err = perr
+					}
+
+					if canceller.IsCancelled() {
+						return
+					}
+
+					if err != nil {
+						subscriber.OnError(err)
+						return
+					}
+
+					if t != nil {
+						subscriber.OnNext(t)
+					}
+					subscriber.OnComplete()
+				})
+				planned = true
+				mux.Unlock()
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
+}
+
 // Empty creates new cesium.Mono that emits no items and completes normally.
 func MonoEmpty() cesium.Mono {
 	return monoFromCallable(func() (cesium.T, bool) {
@@ -118,7 +188,7 @@ func MonoError(err error) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{OnSubscribe: onPublish}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
 }
 
 // Never creates new cesium.Mono that emits no items and never completes.
@@ -139,7 +209,7 @@ func MonoNever() cesium.Mono {
 		return sub
 	}
 
-	return &Mono{OnSubscribe: onPublish}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
 }
 
 // Defer creates new cesium.Mono by subscribing to the Mono returned from the supplied
@@ -154,8 +224,13 @@ func MonoDefer(f func() cesium.Mono) cesium.Mono {
 		subscriptionMux := sync.Mutex{}
 
 		canc := scheduler.Schedule(func(c cesium.Canceller) {
+			var mono cesium.Mono
+			if err := callOperator(func() { mono = f() }, nil); err != nil {
+				mono = MonoError(err)
+			}
+
 			subscriptionMux.Lock()
-			subscription = f().Subscribe(subscriber)
+			subscription = MonoFrom(mono).Subscribe(subscriber)
 			subscriptionMux.Unlock()
 		})
 
@@ -182,7 +257,7 @@ func MonoDefer(f func() cesium.Mono) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{OnSubscribe: onPublish}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
 }
 
 // Using Uses a resource, generated by a supplier for each individual Subscriber,
@@ -196,7 +271,11 @@ func MonoUsing(resourceSupplier func() cesium.T, sourceSupplier func(cesium.T) c
 
 		var subscription cesium.Subscription
 		subscriptionMux := sync.Mutex{}
-		resource := resourceSupplier()
+
+		var resource cesium.T
+		if err := callOperator(func() { resource = resourceSupplier() }, nil); err != nil {
+			return MonoError(err).Subscribe(subscriber)
+		}
 
 		canc := scheduler.Schedule(func(c cesium.Canceller) {
 			subscriptionMux.Lock()
@@ -206,7 +285,12 @@ func MonoUsing(resourceSupplier func() cesium.T, sourceSupplier func(cesium.T) c
 
 			subscription = p.Subscribe(subscriber)
 			subscriber.OnSubscribe(subscription)
-			secondarySubscription := sourceSupplier(resource).Subscribe(p)
+			var source cesium.Mono
+			if err := callOperator(func() { source = sourceSupplier(resource) }, nil); err != nil {
+				source = MonoError(err)
+			}
+
+			secondarySubscription := MonoFrom(source).Subscribe(p)
 			p.OnSubscribe(secondarySubscription)
 
 			subscriptionMux.Unlock()
@@ -235,7 +319,7 @@ func MonoUsing(resourceSupplier func() cesium.T, sourceSupplier func(cesium.T) c
 		return sub
 	}
 
-	return &Mono{OnSubscribe: onPublish}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
 }
 
 // Create allows you to programmatically create a cesium.Mono with the
@@ -254,7 +338,10 @@ func MonoCreate(f func(cesium.MonoSink)) cesium.Mono {
 			sinkMux.Lock()
 			sink = BufferMonoSink(subscriber, c)
 			sinkMux.Unlock()
-			f(sink)
+
+			if err := callOperator(func() { f(sink) }, nil); err != nil {
+				sink.Error(err)
+			}
 		})
 
 		for {
@@ -279,87 +366,109 @@ func MonoCreate(f func(cesium.MonoSink)) cesium.Mono {
 		return sub
 	}
 
-	return &Mono{OnSubscribe: onPublish}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
 }
 
+// MonoFromChannel creates new cesium.Mono that emits the first item received
+// from the channel, or completes empty if it is closed, see channelSource.
 func MonoFromChannel(ch <-chan cesium.T) cesium.Mono {
-	subscribed := false
-	mux := sync.Mutex{}
+	c := &channelSource{ch: ch, limit: 1}
 
-	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		mux.Lock()
-		if subscribed {
-			panic("can not resubscribe to flux made from a channel")
-		}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: c.subscribe})
+}
 
-		subscribed = true
-		mux.Unlock()
+// monoDelay creates new cesium.Mono that emits int64(0) once the delay passes
+// after subscription. If the item was not requested by then, it is emitted upon
+// the first request.
+func monoDelay(delay time.Duration, scheduler cesium.TimedScheduler) cesium.Mono {
+	onPublish := func(subscriber cesium.Subscriber, s cesium.Scheduler) cesium.Subscription {
+		mux := sync.Mutex{}
+		requested := false
+		elapsed := false
+		emitted := false
 
-		if scheduler == nil {
-			scheduler = SeparateGoroutineScheduler()
-		}
+		emit := func() {
+			mux.Lock()
+			if emitted || !requested || !elapsed {
+				mux.Unlock()
+				return
+			}
+			emitted = true
+			mux.Unlock()
 
-		requestedMux := sync.Mutex{}
-		requested := int64(0)
-		unbounded := false
+			subscriber.OnNext(int64(0))
+			subscriber.OnComplete()
+		}
 
-		canc := scheduler.Schedule(func(c cesium.Canceller) {
+		cancellable := scheduler.ScheduleAfter(delay, func(c cesium.Canceller) {
 			if c.IsCancelled() {
 				return
 			}
 
-			for {
-				requestedMux.Lock()
-				if requested == 0 {
-					requestedMux.Unlock()
-					continue
-				}
-				requestedMux.Unlock()
+			mux.Lock()
+			elapsed = true
+			mux.Unlock()
 
-				select {
-				case t, ok := <-ch:
-					if c.IsCancelled() {
-						return
-					}
-
-					if ok {
-						mux.Lock()
-						requested--
-						mux.Unlock()
-						subscriber.OnNext(t)
-						subscriber.OnComplete()
-						return
-					} else {
-						subscriber.OnComplete()
-						return
-					}
-				case <-time.After(time.Millisecond):
-					if c.IsCancelled() {
-						return
-					}
-				}
-			}
+			emit()
 		})
 
 		sub := &Subscription{
 			CancelFunc: func() {
-				canc.Cancel()
+				mux.Lock()
+				emitted = true
+				mux.Unlock()
+
+				cancellable.Cancel()
 			},
 			RequestFunc: func(n int64) {
-				requestedMux.Lock()
-				if unbounded {
-					requestedMux.Unlock()
-					return
-				}
+				mux.Lock()
+				requested = true
+				mux.Unlock()
+
+				emit()
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
+}
+
+// MonoWhenDelayError creates new cesium.Mono that completes empty once all the
+// supplied monos terminate. If any of them fails, the errors are emitted as a
+// cesium.CompositeError after the rest of them terminate.
+func MonoWhenDelayError(monos ...cesium.Mono) cesium.Mono {
+	var pubs []cesium.Publisher
+	for _, m := range monos {
+		pubs = append(pubs, m)
+	}
+
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		upstream := FluxMergeDelayError(pubs...).Subscribe(DoObserver(
+			func(t cesium.T) {},
+			func() {
+				subscriber.OnComplete()
+			},
+			func(err error) {
+				subscriber.OnError(err)
+			},
+		))
 
-				requested = requested + n
-				requestedMux.Unlock()
+		sub := &Subscription{
+			CancelFunc: func() {
+				upstream.Cancel()
 			},
+			RequestFunc: func(n int64) {},
 		}
 
 		subscriber.OnSubscribe(sub)
+
+		// The items are ignored, so there is no reason to apply backpressure.
+		upstream.RequestUnbounded()
 		return sub
 	}
 
-	return &Mono{OnSubscribe: onPublish}
+	return onMonoAssembly(nil, &Mono{OnSubscribe: onPublish})
 }
@@ -19,6 +19,10 @@ func (s *ScalarMono) Map(f func(cesium.T) cesium.T) cesium.Mono {
 	return MonoMapOperator(s, f)
 }
 
+func (s *ScalarMono) MapE(f func(cesium.T) (cesium.T, error)) cesium.Mono {
+	return MonoMapEOperator(s, f)
+}
+
 func (s *ScalarMono) FlatMap(fn func(cesium.T) cesium.Mono, scheduler ...cesium.Scheduler) cesium.Mono {
 	t, ok := s.Get()
 	if ok {
@@ -36,7 +40,10 @@ func (s *ScalarMono) Handle(fn func(cesium.T, cesium.SynchronousSink)) cesium.Mo
 	}
 
 	sink := &SynchronousSink{}
-	fn(t, sink)
+	if err := callOperator(func() { fn(t, sink) }, t); err != nil {
+		return MonoError(err)
+	}
+
 	sig := sink.Signal()
 	if sig != nil {
 		switch sig.Type() {
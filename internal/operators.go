@@ -1,10 +1,6 @@
 package internal
 
-import (
-	"fmt"
-
-	"github.com/DusanKasan/cesium"
-)
+import "github.com/DusanKasan/cesium"
 
 func FluxCountOperator(pub cesium.Publisher) cesium.Mono {
 	switch publisher := pub.(type) {
@@ -37,49 +33,9 @@ func FluxCountOperator(pub cesium.Publisher) cesium.Mono {
 			return sub
 		}
 
-		return &Mono{onPublish}
+		return onMonoAssembly(pub, &Mono{OnSubscribe: onPublish})
 	default:
-		panic(fmt.Sprintf("invalid publisher type: %v", pub))
-		//	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
-		//		requested := int64(0)
-		//		mux := sync.Mutex{}
-		//		var sub cesium.Subscription
-		//
-		//		p := CountProcessor()
-		//
-		//		cancellable := scheduler.Schedule(func(c cesium.Canceller) {
-		//			if c.IsCancelled() {
-		//				return
-		//			}
-		//
-		//			p.Subscribe(subscriber)
-		//			s := publisher.Subscribe(p)
-		//			mux.Lock()
-		//			sub = s
-		//			if requested > 0 {
-		//				sub.Request(requested)
-		//			}
-		//			mux.Unlock()
-		//		})
-		//
-		//		return &Subscription{
-		//			func() {
-		//				sub.Cancel()
-		//				cancellable.Cancel()
-		//			},
-		//			func(i int64) {
-		//				mux.Lock()
-		//				if sub == nil {
-		//					requested = requested + i
-		//				} else {
-		//					sub.Request(i)
-		//				}
-		//				mux.Unlock()
-		//			},
-		//		}
-		//	}
-		//
-		//	return &Mono{onPublish}
+		return FluxCountOperator(FluxFrom(pub))
 	}
 }
 
@@ -87,13 +43,22 @@ func FluxFilterOperator(pub cesium.Publisher, f func(cesium.T) bool) cesium.Flux
 	switch publisher := pub.(type) {
 	case *ScalarFlux:
 		t, ok := publisher.Get()
-		if ok && f(t) {
-			return publisher
+		if ok {
+			var keep bool
+			if err := callOperator(func() { keep = f(t) }, t); err != nil {
+				return FluxError(err)
+			}
+
+			if keep {
+				return publisher
+			}
 		}
 
 		return fluxFromCallable(func() (cesium.T, bool) {
 			return nil, false
 		})
+	case *FuseableFlux:
+		return publisher.Filter(f)
 	case *Flux:
 		onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
 			p := FilterProcessor(f)
@@ -116,9 +81,9 @@ func FluxFilterOperator(pub cesium.Publisher, f func(cesium.T) bool) cesium.Flux
 			return sub
 		}
 
-		return &Flux{onPublish}
+		return onFluxAssembly(pub, &Flux{OnSubscribe: onPublish})
 	default:
-		panic(fmt.Sprintf("invalid publisher type: %v", pub))
+		return FluxFilterOperator(FluxFrom(pub), f)
 	}
 }
 
@@ -128,12 +93,16 @@ func FluxMapOperator(pub cesium.Publisher, f func(t cesium.T) cesium.T) cesium.F
 		t, ok := publisher.Get()
 
 		if ok {
-			t = f(t)
+			if err := callOperator(func() { t = f(t) }, t); err != nil {
+				return FluxError(err)
+			}
 		}
 
 		return fluxFromCallable(func() (cesium.T, bool) {
 			return t, ok
 		})
+	case *FuseableFlux:
+		return publisher.Map(f)
 	case *Flux:
 		onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
 			p := MapProcessor(f)
@@ -156,9 +125,58 @@ func FluxMapOperator(pub cesium.Publisher, f func(t cesium.T) cesium.T) cesium.F
 			return sub
 		}
 
-		return &Flux{onPublish}
+		return onFluxAssembly(pub, &Flux{OnSubscribe: onPublish})
+	default:
+		return FluxMapOperator(FluxFrom(pub), f)
+	}
+}
+
+func FluxMapEOperator(pub cesium.Publisher, f func(t cesium.T) (cesium.T, error)) cesium.Flux {
+	switch publisher := pub.(type) {
+	case *ScalarFlux:
+		t, ok := publisher.Get()
+
+		if ok {
+			var mapped cesium.T
+			var err error
+			if panicErr := callOperator(func() { mapped, err = f(t) }, t); panicErr != nil {
+				return FluxError(panicErr)
+			}
+
+			if err != nil {
+				return FluxError(onOperatorError(err, t))
+			}
+			t = mapped
+		}
+
+		return fluxFromCallable(func() (cesium.T, bool) {
+			return t, ok
+		})
+	case *Flux:
+		onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+			p := MapEProcessor(f)
+
+			subscription1 := p.Subscribe(subscriber)
+			subscription2 := publisher.OnSubscribe(p, scheduler)
+			p.OnSubscribe(subscription2)
+
+			sub := &Subscription{
+				CancelFunc: func() {
+					subscription1.Cancel()
+					subscription2.Cancel()
+				},
+				RequestFunc: func(n int64) {
+					subscription1.Request(n)
+				},
+			}
+
+			subscriber.OnSubscribe(sub)
+			return sub
+		}
+
+		return onFluxAssembly(pub, &Flux{OnSubscribe: onPublish})
 	default:
-		panic(fmt.Sprintf("invalid publisher type: %v", pub))
+		return FluxMapEOperator(FluxFrom(pub), f)
 	}
 }
 
@@ -166,8 +184,15 @@ func MonoFilterOperator(pub cesium.Publisher, f func(t cesium.T) bool) cesium.Mo
 	switch publisher := pub.(type) {
 	case *ScalarMono:
 		t, ok := publisher.Get()
-		if ok && f(t) {
-			return publisher
+		if ok {
+			var keep bool
+			if err := callOperator(func() { keep = f(t) }, t); err != nil {
+				return MonoError(err)
+			}
+
+			if keep {
+				return publisher
+			}
 		}
 
 		return monoFromCallable(func() (cesium.T, bool) {
@@ -195,9 +220,9 @@ func MonoFilterOperator(pub cesium.Publisher, f func(t cesium.T) bool) cesium.Mo
 			return sub
 		}
 
-		return &Mono{onPublish}
+		return onMonoAssembly(pub, &Mono{OnSubscribe: onPublish})
 	default:
-		panic(fmt.Sprintf("invalid publisher type: %v", pub))
+		return MonoFilterOperator(MonoFrom(pub), f)
 	}
 }
 
@@ -207,7 +232,9 @@ func MonoMapOperator(pub cesium.Publisher, f func(t cesium.T) cesium.T) cesium.M
 		t, ok := publisher.Get()
 
 		if ok {
-			t = f(t)
+			if err := callOperator(func() { t = f(t) }, t); err != nil {
+				return MonoError(err)
+			}
 		}
 
 		return monoFromCallable(func() (cesium.T, bool) {
@@ -235,8 +262,169 @@ func MonoMapOperator(pub cesium.Publisher, f func(t cesium.T) cesium.T) cesium.M
 			return sub
 		}
 
-		return &Mono{onPublish}
+		return onMonoAssembly(pub, &Mono{OnSubscribe: onPublish})
 	default:
-		panic(fmt.Sprintf("invalid publisher type: %v", pub))
+		return MonoMapOperator(MonoFrom(pub), f)
 	}
 }
+
+func MonoMapEOperator(pub cesium.Publisher, f func(t cesium.T) (cesium.T, error)) cesium.Mono {
+	switch publisher := pub.(type) {
+	case *ScalarMono:
+		t, ok := publisher.Get()
+
+		if ok {
+			var mapped cesium.T
+			var err error
+			if panicErr := callOperator(func() { mapped, err = f(t) }, t); panicErr != nil {
+				return MonoError(panicErr)
+			}
+
+			if err != nil {
+				return MonoError(onOperatorError(err, t))
+			}
+			t = mapped
+		}
+
+		return monoFromCallable(func() (cesium.T, bool) {
+			return t, ok
+		})
+	case *Mono:
+		onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+			p := MapEProcessor(f)
+
+			subscription1 := p.Subscribe(subscriber)
+			subscription2 := publisher.OnSubscribe(p, scheduler)
+			p.OnSubscribe(subscription2)
+
+			sub := &Subscription{
+				CancelFunc: func() {
+					subscription1.Cancel()
+					subscription2.Cancel()
+				},
+				RequestFunc: func(n int64) {
+					subscription1.Request(n)
+				},
+			}
+
+			subscriber.OnSubscribe(sub)
+			return sub
+		}
+
+		return onMonoAssembly(pub, &Mono{OnSubscribe: onPublish})
+	default:
+		return MonoMapEOperator(MonoFrom(pub), f)
+	}
+}
+
+// FluxTimestampOperator is not fused with scalar publishers, the timestamp
+// is taken upon each emission, not upon assembly.
+func FluxTimestampOperator(pub cesium.Publisher, clock cesium.Clock) cesium.Flux {
+	source := subscribeFunc(FluxFrom(pub))
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := TimestampProcessor(clock)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := source(p, scheduler)
+		p.OnSubscribe(subscription2)
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onFluxAssembly(pub, &Flux{OnSubscribe: onPublish})
+}
+
+// FluxElapsedOperator is not fused with scalar publishers, the elapsed time
+// is measured for each subscription.
+func FluxElapsedOperator(pub cesium.Publisher, clock cesium.Clock) cesium.Flux {
+	source := subscribeFunc(FluxFrom(pub))
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := ElapsedProcessor(clock)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := source(p, scheduler)
+		p.OnSubscribe(subscription2)
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onFluxAssembly(pub, &Flux{OnSubscribe: onPublish})
+}
+
+// MonoTimestampOperator is not fused with scalar publishers, the timestamp
+// is taken upon each emission, not upon assembly.
+func MonoTimestampOperator(pub cesium.Publisher, clock cesium.Clock) cesium.Mono {
+	source := subscribeFunc(MonoFrom(pub))
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := TimestampProcessor(clock)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := source(p, scheduler)
+		p.OnSubscribe(subscription2)
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(pub, &Mono{OnSubscribe: onPublish})
+}
+
+// MonoElapsedOperator is not fused with scalar publishers, the elapsed time
+// is measured for each subscription.
+func MonoElapsedOperator(pub cesium.Publisher, clock cesium.Clock) cesium.Mono {
+	source := subscribeFunc(MonoFrom(pub))
+	onPublish := func(subscriber cesium.Subscriber, scheduler cesium.Scheduler) cesium.Subscription {
+		p := ElapsedProcessor(clock)
+
+		subscription1 := p.Subscribe(subscriber)
+		subscription2 := source(p, scheduler)
+		p.OnSubscribe(subscription2)
+
+		sub := &Subscription{
+			CancelFunc: func() {
+				subscription1.Cancel()
+				subscription2.Cancel()
+			},
+			RequestFunc: func(n int64) {
+				subscription1.Request(n)
+			},
+		}
+
+		subscriber.OnSubscribe(sub)
+		return sub
+	}
+
+	return onMonoAssembly(pub, &Mono{OnSubscribe: onPublish})
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// callSafely calls the user supplied function and returns the panic it raised,
+// if any, as a *cesium.PanicError.
+func callSafely(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &cesium.PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	f()
+	return nil
+}
+
+// callOperator calls the user supplied function of an operator processing the
+// item and returns the panic it raised, if any, mapped by the OnOperatorError
+// hook.
+func callOperator(f func(), t cesium.T) error {
+	if err := callSafely(f); err != nil {
+		return onOperatorError(err, t)
+	}
+
+	return nil
+}
+
+// terminatingSubscriber drops the signals it receives after a terminal one, so
+// that a processor that failed with a panic does not emit anything after the
+// resulting error.
+type terminatingSubscriber struct {
+	cesium.Subscriber
+	terminated int32
+}
+
+func (s *terminatingSubscriber) OnNext(t cesium.T) {
+	if atomic.LoadInt32(&s.terminated) != 0 {
+		onNextDropped(t)
+		return
+	}
+
+	s.Subscriber.OnNext(t)
+}
+
+func (s *terminatingSubscriber) OnComplete() {
+	if atomic.CompareAndSwapInt32(&s.terminated, 0, 1) {
+		s.Subscriber.OnComplete()
+	}
+}
+
+func (s *terminatingSubscriber) OnError(err error) {
+	if !atomic.CompareAndSwapInt32(&s.terminated, 0, 1) {
+		onErrorDropped(err)
+		return
+	}
+
+	s.Subscriber.OnError(err)
+}
+
+func (s *terminatingSubscriber) errorContinue() func(error, cesium.T) {
+	return errorContinueOf(s.Subscriber)
+}
+
+// conditionalTerminatingSubscriber is a terminatingSubscriber that keeps the
+// microfusion with a downstream ConditionalSubscriber.
+type conditionalTerminatingSubscriber struct {
+	*terminatingSubscriber
+	conditional ConditionalSubscriber
+}
+
+func (s *conditionalTerminatingSubscriber) OnNextIf(t cesium.T) bool {
+	if atomic.LoadInt32(&s.terminated) != 0 {
+		onNextDropped(t)
+		return false
+	}
+
+	return s.conditional.OnNextIf(t)
+}
+
+func newTerminatingSubscriber(s cesium.Subscriber) cesium.Subscriber {
+	t := &terminatingSubscriber{Subscriber: s}
+	if c, ok := s.(ConditionalSubscriber); ok {
+		return &conditionalTerminatingSubscriber{t, c}
+	}
+
+	return t
+}
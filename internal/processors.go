@@ -1,21 +1,41 @@
 package internal
 
 import (
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 
 	"math"
 
+	"time"
+
 	"github.com/DusanKasan/cesium"
 )
 
 //NOTE: processor can only subscribe to one publisher
 
 type processor struct {
+	// The demand passed upstream through request, see drainRequests. It comes
+	// first to be aligned for the atomic operations.
+	requested int64
+
 	onNext      func(cesium.T)
 	onComplete  func()
 	onError     func(error)
 	onSubscribe func(cesium.Subscription)
 	subscribe   func(cesium.Subscriber) cesium.Subscription
+
+	// The upstream subscription and the downstream subscriber are kept so
+	// that a panic in any of the callbacks can be turned into an error.
+	mux           sync.Mutex
+	upstream      cesium.Subscription
+	downstream    cesium.Subscriber
+	failed        int32
+	errorStrategy func(error, cesium.T)
+
+	// The unbounded flag is only accessed by the loop passing the demand.
+	requesting int32
+	unbounded  bool
 }
 
 type conditionalProcessor struct {
@@ -24,27 +44,183 @@ type conditionalProcessor struct {
 }
 
 func (c *conditionalProcessor) OnNextIf(t cesium.T) bool {
+	if c.isFailed() {
+		onNextDropped(t)
+		return false
+	}
+	defer c.recoverPanic(t)
+
 	return c.onNextIf(t)
 }
 
 func (p *processor) OnNext(t cesium.T) {
+	if p.isFailed() {
+		onNextDropped(t)
+		return
+	}
+	defer p.recoverPanic(t)
+
 	p.onNext(t)
 }
 
 func (p *processor) OnComplete() {
+	if p.isFailed() {
+		return
+	}
+	defer p.recoverPanic(nil)
+
 	p.onComplete()
 }
 
 func (p *processor) OnError(err error) {
+	if p.isFailed() {
+		onErrorDropped(err)
+		return
+	}
+	defer p.recoverPanic(nil)
+
 	p.onError(err)
 }
 
 func (p *processor) OnSubscribe(s cesium.Subscription) {
+	if s != nil {
+		p.mux.Lock()
+		p.upstream = s
+		p.mux.Unlock()
+
+		if p.isFailed() {
+			s.Cancel()
+			return
+		}
+	}
+	defer p.recoverPanic(nil)
+
 	p.onSubscribe(s)
+	p.drainRequests()
+}
+
+// request requests n more items from upstream. The demand requested before
+// the upstream subscription is received is passed on once it is.
+func (p *processor) request(n int64) {
+	addDemandAtomic(&p.requested, n)
+	p.drainRequests()
+}
+
+// drainRequests passes the outstanding demand upstream. Only one goroutine
+// passes it at a time, the requests made meanwhile, like the replacements for
+// the items rejected by an upstream emitting from within Request, only
+// increment the work-in-progress counter and are passed by the loop already
+// running instead of recursing into it. No lock is held while requesting.
+func (p *processor) drainRequests() {
+	if atomic.AddInt32(&p.requesting, 1) != 1 {
+		return
+	}
+
+	missed := int32(1)
+	for {
+		p.mux.Lock()
+		upstream := p.upstream
+		p.mux.Unlock()
+
+		if upstream != nil && !p.unbounded {
+			if n := takeDemand(&p.requested); n > 0 {
+				p.unbounded = n == math.MaxInt64
+				upstream.Request(n)
+			}
+		}
+
+		missed = atomic.AddInt32(&p.requesting, -missed)
+		if missed == 0 {
+			return
+		}
+	}
 }
 
 func (p *processor) Subscribe(s cesium.Subscriber) cesium.Subscription {
-	return p.subscribe(s)
+	downstream := newTerminatingSubscriber(s)
+
+	p.mux.Lock()
+	p.downstream = downstream
+	p.errorStrategy = errorContinueOf(s)
+	p.mux.Unlock()
+
+	return p.subscribe(downstream)
+}
+
+func (p *processor) errorContinue() func(error, cesium.T) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.errorStrategy
+}
+
+// continueOnError passes the item that failed with the error to the
+// OnErrorContinue strategy set up downstream, if there is one. It reports
+// whether the item should be dropped, in which case the caller is responsible
+// for requesting a replacement from upstream.
+func (p *processor) continueOnError(err error, t cesium.T) bool {
+	strategy := p.errorContinue()
+	if strategy == nil {
+		return false
+	}
+
+	strategy(err, t)
+	return true
+}
+
+// apply calls the user supplied function for the item. If it panics while
+// there is an OnErrorContinue strategy set up downstream, the item should be
+// dropped and false is returned. Otherwise the panic is left to recoverPanic.
+func (p *processor) apply(t cesium.T, f func()) bool {
+	if p.errorContinue() == nil {
+		f()
+		return true
+	}
+
+	if err := callSafely(f); err != nil {
+		return !p.continueOnError(err, t)
+	}
+
+	return true
+}
+
+func (p *processor) isFailed() bool {
+	return atomic.LoadInt32(&p.failed) != 0
+}
+
+// recoverPanic turns a panic raised by a user supplied function while
+// processing the item into a *cesium.PanicError emitted downstream, cancelling
+// the upstream subscription. The signals received afterwards are dropped. It
+// has to be deferred directly.
+func (p *processor) recoverPanic(t cesium.T) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	p.fail(onOperatorError(&cesium.PanicError{Value: r, Stack: debug.Stack()}, t))
+}
+
+// fail emits the error downstream and cancels the upstream subscription,
+// unless the processor failed already. The signals received afterwards are
+// dropped.
+func (p *processor) fail(err error) {
+	if !atomic.CompareAndSwapInt32(&p.failed, 0, 1) {
+		onErrorDropped(err)
+		return
+	}
+
+	p.mux.Lock()
+	upstream, downstream := p.upstream, p.downstream
+	p.mux.Unlock()
+
+	if upstream != nil {
+		upstream.Cancel()
+	}
+
+	if downstream != nil {
+		downstream.OnError(err)
+	}
 }
 
 func FilterProcessor(f func(cesium.T) bool) cesium.Processor {
@@ -53,7 +229,8 @@ func FilterProcessor(f func(cesium.T) bool) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	p := &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -64,13 +241,7 @@ func FilterProcessor(f func(cesium.T) bool) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscriptionMux.Unlock()
-						subscription.Request(n)
-						return
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -87,14 +258,16 @@ func FilterProcessor(f func(cesium.T) bool) cesium.Processor {
 			subscriptionMux.Unlock()
 		},
 		onNext: func(t cesium.T) {
-			if f(t) {
+			// A dropped item is treated as filtered out.
+			keep := false
+			p.apply(t, func() { keep = f(t) })
+
+			if keep {
 				subscriberMux.Lock()
 				subscriber.OnNext(t)
 				subscriberMux.Unlock()
 			} else {
-				subscriptionMux.Lock()
-				subscription.Request(1)
-				subscriptionMux.Unlock()
+				p.request(1)
 			}
 		},
 		onComplete: func() {
@@ -112,7 +285,8 @@ func FilterProcessor(f func(cesium.T) bool) cesium.Processor {
 	return &conditionalProcessor{
 		p,
 		func(t cesium.T) bool {
-			b := f(t)
+			b := false
+			p.apply(t, func() { b = f(t) })
 
 			if b {
 				switch sub := subscriber.(type) {
@@ -140,7 +314,8 @@ func MapProcessor(f func(cesium.T) cesium.T) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -151,12 +326,72 @@ func MapProcessor(f func(cesium.T) cesium.T) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
+					p.request(n)
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			subscriptionMux.Unlock()
+		},
+		onNext: func(t cesium.T) {
+			var mapped cesium.T
+			if !p.apply(t, func() { mapped = f(t) }) {
+				p.request(1)
+				return
+			}
+
+			subscriberMux.Lock()
+			subscriber.OnNext(mapped)
+			subscriberMux.Unlock()
+		},
+		onComplete: func() {
+			subscriberMux.Lock()
+			subscriber.OnComplete()
+			subscriberMux.Unlock()
+		},
+		onError: func(err error) {
+			subscriberMux.Lock()
+			subscriber.OnError(err)
+			subscriberMux.Unlock()
+		},
+	}
+
+	return p
+}
+
+// MapEProcessor maps the items with a function that can fail. The first error
+// is emitted downstream and the upstream subscription is cancelled.
+func MapEProcessor(f func(cesium.T) (cesium.T, error)) cesium.Processor {
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	terminated := false
+
+	var p *processor
+	p = &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
 					subscriptionMux.Lock()
 					if subscription != nil {
-						subscription.Request(n)
+						subscription.Cancel()
 					}
 					subscriptionMux.Unlock()
 				},
+				RequestFunc: func(n int64) {
+					p.request(n)
+				},
 			}
 
 			subscriberMux.Lock()
@@ -173,20 +408,62 @@ func MapProcessor(f func(cesium.T) cesium.T) cesium.Processor {
 		},
 		onNext: func(t cesium.T) {
 			subscriberMux.Lock()
-			subscriber.OnNext(f(t))
+			if terminated {
+				subscriberMux.Unlock()
+				onNextDropped(t)
+				return
+			}
+
+			var mapped cesium.T
+			var err error
+			if !p.apply(t, func() { mapped, err = f(t) }) {
+				subscriberMux.Unlock()
+				p.request(1)
+				return
+			}
+
+			if err == nil {
+				subscriber.OnNext(mapped)
+				subscriberMux.Unlock()
+				return
+			}
+
+			if p.continueOnError(err, t) {
+				subscriberMux.Unlock()
+				p.request(1)
+				return
+			}
+
+			terminated = true
+			subscriptionMux.Lock()
+			subscription.Cancel()
+			subscriptionMux.Unlock()
+
+			subscriber.OnError(onOperatorError(err, t))
 			subscriberMux.Unlock()
 		},
 		onComplete: func() {
 			subscriberMux.Lock()
-			subscriber.OnComplete()
+			if !terminated {
+				terminated = true
+				subscriber.OnComplete()
+			}
 			subscriberMux.Unlock()
 		},
 		onError: func(err error) {
 			subscriberMux.Lock()
+			if terminated {
+				subscriberMux.Unlock()
+				onErrorDropped(err)
+				return
+			}
+			terminated = true
 			subscriber.OnError(err)
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func DoFinallyProcessor(f func()) cesium.Processor {
@@ -195,7 +472,8 @@ func DoFinallyProcessor(f func()) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -207,11 +485,7 @@ func DoFinallyProcessor(f func()) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -245,6 +519,8 @@ func DoFinallyProcessor(f func()) cesium.Processor {
 			f()
 		},
 	}
+
+	return p
 }
 
 func CountProcessor() cesium.Processor {
@@ -255,7 +531,8 @@ func CountProcessor() cesium.Processor {
 
 	count := int64(0)
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -266,11 +543,7 @@ func CountProcessor() cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
-					}
-					subscriptionMux.Unlock()
+					p.request(1)
 				},
 			}
 
@@ -288,7 +561,7 @@ func CountProcessor() cesium.Processor {
 		},
 		onNext: func(t cesium.T) {
 			count++
-			subscription.Request(1)
+			p.request(1)
 		},
 		onComplete: func() {
 			subscriberMux.Lock()
@@ -302,6 +575,8 @@ func CountProcessor() cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func ReduceProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
@@ -314,7 +589,8 @@ func ReduceProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 	var previousItem cesium.T
 	mux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -325,11 +601,7 @@ func ReduceProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
-					}
-					subscriptionMux.Unlock()
+					p.request(1)
 				},
 			}
 
@@ -354,7 +626,7 @@ func ReduceProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 				previousItem = f(previousItem, t)
 			}
 			mux.Unlock()
-			subscription.Request(1)
+			p.request(1)
 		},
 		onComplete: func() {
 			subscriberMux.Lock()
@@ -368,6 +640,8 @@ func ReduceProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func ScanProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
@@ -380,7 +654,8 @@ func ScanProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 	var previousItem cesium.T
 	mux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -391,11 +666,7 @@ func ScanProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
-					}
-					subscriptionMux.Unlock()
+					p.request(1)
 				},
 			}
 
@@ -419,10 +690,10 @@ func ScanProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 			} else {
 				previousItem = f(previousItem, t)
 			}
-			p := previousItem
+			item := previousItem
 			mux.Unlock()
-			subscriber.OnNext(p)
-			subscription.Request(1)
+			subscriber.OnNext(item)
+			p.request(1)
 		},
 		onComplete: func() {
 			subscriberMux.Lock()
@@ -435,15 +706,21 @@ func ScanProcessor(f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
-func AllProcessor(f func(cesium.T) bool) cesium.Processor {
+// ScanWithProcessor emits the seed first and then the result of accumulating
+// each item into the previous result.
+func ScanWithProcessor(seed cesium.T, f func(cesium.T, cesium.T) cesium.T) cesium.Processor {
 	var subscriber cesium.Subscriber
 	var subscription cesium.Subscription
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	closed := false
+	seedEmitted := false
+	accumulated := seed
+	mux := sync.Mutex{}
 
 	return &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
@@ -456,9 +733,24 @@ func AllProcessor(f func(cesium.T) bool) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
+					mux.Lock()
+					emitSeed := !seedEmitted
+					seedEmitted = true
+					mux.Unlock()
+
+					if emitSeed {
+						subscriberMux.Lock()
+						subscriber.OnNext(seed)
+						subscriberMux.Unlock()
+
+						if n != math.MaxInt64 {
+							n--
+						}
+					}
+
 					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
+					if subscription != nil && n > 0 {
+						subscription.Request(n)
 					}
 					subscriptionMux.Unlock()
 				},
@@ -477,42 +769,29 @@ func AllProcessor(f func(cesium.T) bool) cesium.Processor {
 			subscriptionMux.Unlock()
 		},
 		onNext: func(t cesium.T) {
-			if f(t) {
-				subscriptionMux.Lock()
-				subscription.Request(1)
-				subscriptionMux.Unlock()
-				return
-			} else {
-				subscriberMux.Lock()
-				closed = true
-				subscriber.OnNext(false)
-				subscriber.OnComplete()
-				subscriberMux.Unlock()
+			mux.Lock()
+			accumulated = f(accumulated, t)
+			a := accumulated
+			mux.Unlock()
 
-				subscriptionMux.Lock()
-				subscription.Cancel()
-				subscriptionMux.Unlock()
-			}
+			subscriberMux.Lock()
+			subscriber.OnNext(a)
+			subscriberMux.Unlock()
 		},
 		onComplete: func() {
 			subscriberMux.Lock()
-			if !closed {
-				subscriber.OnNext(true)
-				subscriber.OnComplete()
-			}
+			subscriber.OnComplete()
 			subscriberMux.Unlock()
 		},
 		onError: func(err error) {
 			subscriberMux.Lock()
-			if !closed {
-				subscriber.OnError(err)
-			}
+			subscriber.OnError(err)
 			subscriberMux.Unlock()
 		},
 	}
 }
 
-func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
+func AllProcessor(f func(cesium.T) bool) cesium.Processor {
 	var subscriber cesium.Subscriber
 	var subscription cesium.Subscription
 	subscriberMux := sync.Mutex{}
@@ -520,7 +799,8 @@ func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
 
 	closed := false
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -531,11 +811,7 @@ func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
-					}
-					subscriptionMux.Unlock()
+					p.request(1)
 				},
 			}
 
@@ -552,15 +828,15 @@ func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
 			subscriptionMux.Unlock()
 		},
 		onNext: func(t cesium.T) {
-			if !f(t) {
+			if f(t) {
 				subscriptionMux.Lock()
-				subscription.Request(1)
+				p.request(1)
 				subscriptionMux.Unlock()
 				return
 			} else {
 				subscriberMux.Lock()
 				closed = true
-				subscriber.OnNext(true)
+				subscriber.OnNext(false)
 				subscriber.OnComplete()
 				subscriberMux.Unlock()
 
@@ -572,7 +848,7 @@ func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
 		onComplete: func() {
 			subscriberMux.Lock()
 			if !closed {
-				subscriber.OnNext(false)
+				subscriber.OnNext(true)
 				subscriber.OnComplete()
 			}
 			subscriberMux.Unlock()
@@ -585,9 +861,11 @@ func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
-func HasElementsProcessor() cesium.Processor {
+func AnyProcessor(f func(cesium.T) bool) cesium.Processor {
 	var subscriber cesium.Subscriber
 	var subscription cesium.Subscription
 	subscriberMux := sync.Mutex{}
@@ -595,7 +873,8 @@ func HasElementsProcessor() cesium.Processor {
 
 	closed := false
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -606,11 +885,7 @@ func HasElementsProcessor() cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
-					}
-					subscriptionMux.Unlock()
+					p.request(1)
 				},
 			}
 
@@ -627,8 +902,82 @@ func HasElementsProcessor() cesium.Processor {
 			subscriptionMux.Unlock()
 		},
 		onNext: func(t cesium.T) {
-			subscriberMux.Lock()
-			closed = true
+			if !f(t) {
+				subscriptionMux.Lock()
+				p.request(1)
+				subscriptionMux.Unlock()
+				return
+			} else {
+				subscriberMux.Lock()
+				closed = true
+				subscriber.OnNext(true)
+				subscriber.OnComplete()
+				subscriberMux.Unlock()
+
+				subscriptionMux.Lock()
+				subscription.Cancel()
+				subscriptionMux.Unlock()
+			}
+		},
+		onComplete: func() {
+			subscriberMux.Lock()
+			if !closed {
+				subscriber.OnNext(false)
+				subscriber.OnComplete()
+			}
+			subscriberMux.Unlock()
+		},
+		onError: func(err error) {
+			subscriberMux.Lock()
+			if !closed {
+				subscriber.OnError(err)
+			}
+			subscriberMux.Unlock()
+		},
+	}
+
+	return p
+}
+
+func HasElementsProcessor() cesium.Processor {
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	closed := false
+
+	var p *processor
+	p = &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
+					subscriptionMux.Lock()
+					if subscription != nil {
+						subscription.Cancel()
+					}
+					subscriptionMux.Unlock()
+				},
+				RequestFunc: func(n int64) {
+					p.request(1)
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			subscriptionMux.Unlock()
+		},
+		onNext: func(t cesium.T) {
+			subscriberMux.Lock()
+			closed = true
 			subscriber.OnNext(true)
 			subscriber.OnComplete()
 			subscriberMux.Unlock()
@@ -653,6 +1002,8 @@ func HasElementsProcessor() cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func DoProcessor(
@@ -668,24 +1019,30 @@ func DoProcessor(
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	// The callbacks of the subscription are called without holding any lock.
+	// A panic in them is emitted downstream as an error, cancelling upstream.
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
+					if err := callOperator(onCancel, nil); err != nil {
+						p.fail(err)
+					}
+
 					subscriptionMux.Lock()
-					onCancel()
 					if subscription != nil {
 						subscription.Cancel()
 					}
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					onRequest(n)
-					if subscription != nil {
-						subscription.Request(n)
+					if err := callOperator(func() { onRequest(n) }, nil); err != nil {
+						p.fail(err)
+						return
 					}
-					subscriptionMux.Unlock()
+
+					p.request(n)
 				},
 			}
 
@@ -698,9 +1055,10 @@ func DoProcessor(
 		},
 		onSubscribe: func(s cesium.Subscription) {
 			subscriptionMux.Lock()
-			onSubscribe(s)
 			subscription = s
 			subscriptionMux.Unlock()
+
+			onSubscribe(s)
 		},
 		onNext: func(t cesium.T) {
 			subscriberMux.Lock()
@@ -721,6 +1079,8 @@ func DoProcessor(
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func DoAfterTerminateProcessor(fn func()) cesium.Processor {
@@ -729,7 +1089,8 @@ func DoAfterTerminateProcessor(fn func()) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -740,11 +1101,7 @@ func DoAfterTerminateProcessor(fn func()) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -778,6 +1135,8 @@ func DoAfterTerminateProcessor(fn func()) cesium.Processor {
 			fn()
 		},
 	}
+
+	return p
 }
 
 func HandleProcessor(fn func(cesium.T, cesium.SynchronousSink)) cesium.Processor {
@@ -788,7 +1147,8 @@ func HandleProcessor(fn func(cesium.T, cesium.SynchronousSink)) cesium.Processor
 
 	terminated := false
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -799,11 +1159,7 @@ func HandleProcessor(fn func(cesium.T, cesium.SynchronousSink)) cesium.Processor
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -823,12 +1179,23 @@ func HandleProcessor(fn func(cesium.T, cesium.SynchronousSink)) cesium.Processor
 			subscriberMux.Lock()
 			if terminated {
 				subscriberMux.Unlock()
+				onNextDropped(t)
 				return
 			}
 
 			sink := &SynchronousSink{}
-			fn(t, sink)
+			if !p.apply(t, func() { fn(t, sink) }) {
+				subscriberMux.Unlock()
+				p.request(1)
+				return
+			}
+
 			sig := sink.Signal()
+			if sig != nil && sig.Type() == cesium.SignalTypeOnError && p.continueOnError(sig.Error(), t) {
+				subscriberMux.Unlock()
+				p.request(1)
+				return
+			}
 			if sig == nil {
 				subscriber.OnError(cesium.NoEmissionOnSynchronousSinkError)
 				terminated = true
@@ -863,15 +1230,30 @@ func HandleProcessor(fn func(cesium.T, cesium.SynchronousSink)) cesium.Processor
 			subscriberMux.Lock()
 			if terminated {
 				subscriberMux.Unlock()
+				onErrorDropped(err)
 				return
 			}
 			subscriber.OnError(err)
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.Processor {
+	return concatProcessor(publishers, false)
+}
+
+// ConcatDelayErrorProcessor works like ConcatProcessor, but an error from one
+// of the sources does not terminate the sequence. The remaining sources are
+// consumed and all the collected errors are emitted as a cesium.CompositeError
+// in the end.
+func ConcatDelayErrorProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.Processor {
+	return concatProcessor(publishers, true)
+}
+
+func concatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/, delayError bool) cesium.Processor {
 	var subscriber cesium.Subscriber
 	var subscription cesium.Subscription
 	subscriberMux := sync.Mutex{}
@@ -881,16 +1263,15 @@ func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.
 
 	mux := sync.Mutex{}
 	publishersComplete := false
-	pendingRequests := int64(0)
-	unbounded := false
+	var errs []error
+
+	// The demand not yet satisfied by the sources, requested from each of
+	// them once it is subscribed to.
+	requested := int64(0)
 
 	p := DoObserver(
 		func(t cesium.T) {
-			s := t.(cesium.Publisher).Subscribe(proc)
-
-			if !unbounded {
-				s.Request(pendingRequests)
-			}
+			FluxFrom(t.(cesium.Publisher)).Subscribe(proc)
 		},
 		func() {
 			mux.Lock()
@@ -898,12 +1279,19 @@ func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.
 			mux.Unlock()
 		},
 		func(err error) {
+			if delayError {
+				// No more sources will come, so the error is the last one.
+				mux.Lock()
+				publishersComplete = true
+				mux.Unlock()
+			}
+
 			MonoError(err).Subscribe(proc).Request(1)
 		},
 	)
 
 	var publishersSubscription cesium.Subscription
-	publishersSubscription = publishers.Subscribe(p)
+	publishersSubscription = FluxFrom(publishers).Subscribe(p)
 
 	proc = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
@@ -917,18 +1305,13 @@ func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.
 				},
 				RequestFunc: func(n int64) {
 					subscriptionMux.Lock()
-					if n == math.MaxInt64 {
-						unbounded = true
-					}
-
-					if !unbounded {
-						pendingRequests = pendingRequests + n
-					}
+					addDemandAtomic(&requested, n)
+					s := subscription
+					subscriptionMux.Unlock()
 
-					if subscription != nil {
-						subscription.Request(n)
+					if s != nil {
+						s.Request(n)
 					}
-					subscriptionMux.Unlock()
 				},
 			}
 
@@ -940,15 +1323,20 @@ func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.
 			return sub
 		},
 		onSubscribe: func(s cesium.Subscription) {
+			// The demand is read together with the subscription swap, so
+			// that a concurrent request is passed to exactly one source.
 			subscriptionMux.Lock()
 			subscription = s
+			n := atomic.LoadInt64(&requested)
 			subscriptionMux.Unlock()
+
+			if s != nil && n > 0 {
+				s.Request(n)
+			}
 		},
 		onNext: func(t cesium.T) {
 			subscriberMux.Lock()
-			if !unbounded {
-				pendingRequests = pendingRequests - 1
-			}
+			produceDemand(&requested, 1)
 			subscriber.OnNext(t)
 			subscriberMux.Unlock()
 		},
@@ -957,7 +1345,11 @@ func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.
 			mux.Lock()
 			if publishersComplete {
 				mux.Unlock()
-				subscriber.OnComplete()
+				if len(errs) > 0 {
+					subscriber.OnError(&cesium.CompositeError{Errors: errs})
+				} else {
+					subscriber.OnComplete()
+				}
 			} else {
 				mux.Unlock()
 				publishersSubscription.Request(1)
@@ -966,6 +1358,20 @@ func ConcatProcessor(publishers cesium.Publisher /*<cesium.Publisher>*/) cesium.
 		},
 		onError: func(err error) {
 			subscriberMux.Lock()
+			if delayError {
+				errs = append(errs, err)
+				mux.Lock()
+				if publishersComplete {
+					mux.Unlock()
+					subscriber.OnError(&cesium.CompositeError{Errors: errs})
+				} else {
+					mux.Unlock()
+					publishersSubscription.Request(1)
+				}
+				subscriberMux.Unlock()
+				return
+			}
+
 			publishersSubscription.Cancel()
 			subscriptionMux.Lock()
 			subscription.Cancel()
@@ -987,7 +1393,8 @@ func DistinctUntilChangedProcessor() cesium.Processor {
 	started := false
 	var item cesium.T
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -998,11 +1405,7 @@ func DistinctUntilChangedProcessor() cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1020,9 +1423,7 @@ func DistinctUntilChangedProcessor() cesium.Processor {
 		},
 		onNext: func(t cesium.T) {
 			if started && item == t {
-				subscriptionMux.Lock()
-				subscription.Request(1)
-				subscriptionMux.Unlock()
+				p.request(1)
 				return
 			}
 
@@ -1043,6 +1444,8 @@ func DistinctUntilChangedProcessor() cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func TakeProcessor(n int64) cesium.Processor {
@@ -1053,7 +1456,8 @@ func TakeProcessor(n int64) cesium.Processor {
 
 	taken := int64(0)
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -1064,11 +1468,7 @@ func TakeProcessor(n int64) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1120,72 +1520,47 @@ func TakeProcessor(n int64) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
-}
 
-type indexedEmission struct {
-	t     cesium.T
-	index int
+	return p
 }
 
-func FlatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Scheduler) cesium.Processor {
+// LimitRequestProcessor caps the total demand sent upstream at n and
+// completes once n items were emitted. The subscription is passed to the
+// subscriber before subscribing upstream, as the processor may complete as
+// soon as it is subscribed, if n is not positive.
+func LimitRequestProcessor(n int64) cesium.Processor {
 	var subscriber cesium.Subscriber
 	var subscription cesium.Subscription
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	var emissionBuffer []indexedEmission
-	subscriptions := make(map[int]cesium.Subscription)
-	mux := sync.Mutex{}
-	hasItems := false
-	closed := false
-	currentIndex := 0
-	openSubscriptions := 0
 	requested := int64(0)
-	mainEmittedAll := false
+	emitted := int64(0)
+	done := false
+	cancelled := false
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
 					subscriptionMux.Lock()
+					cancelled = true
 					if subscription != nil {
 						subscription.Cancel()
 					}
 					subscriptionMux.Unlock()
 				},
-				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					mux.Lock()
-					for len(emissionBuffer) > 0 && n > 0 {
-						emission := emissionBuffer[0]
-						emissionBuffer = emissionBuffer[1:]
-						n--
-
-						scheduler.Schedule(func(c cesium.Canceller) {
-							if !c.IsCancelled() {
-								subscriber.OnNext(emission.t)
-								mux.Lock()
-								subscriptions[emission.index].Request(1)
-								mux.Unlock()
-							}
-						})
-					}
-
-					if openSubscriptions == 0 && mainEmittedAll && len(emissionBuffer) == 0 {
-						scheduler.Schedule(func(c cesium.Canceller) {
-							subscriber.OnComplete()
-						})
+				RequestFunc: func(r int64) {
+					if r = addDemandLimited(&requested, r, n); r > 0 {
+						p.request(r)
 					}
-
-					requested = requested + n
-					mux.Unlock()
-					subscriptionMux.Unlock()
 				},
 			}
 
 			subscriberMux.Lock()
 			subscriber = s
-			subscriber.OnSubscribe(subscription)
+			subscriber.OnSubscribe(sub)
 			subscriberMux.Unlock()
 
 			return sub
@@ -1193,44 +1568,201 @@ func FlatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Schedu
 		onSubscribe: func(s cesium.Subscription) {
 			subscriptionMux.Lock()
 			subscription = s
-			s.Request(math.MaxInt64)
+			if cancelled {
+				subscriptionMux.Unlock()
+				s.Cancel()
+				return
+			}
 			subscriptionMux.Unlock()
+
+			// The subscriber received the subscription already, so it can be
+			// completed right away.
+			if n <= 0 {
+				s.Cancel()
+
+				subscriberMux.Lock()
+				if !done {
+					done = true
+					subscriber.OnComplete()
+				}
+				subscriberMux.Unlock()
+			}
 		},
 		onNext: func(t cesium.T) {
-			mux.Lock()
-			if closed {
-				mux.Unlock()
+			subscriberMux.Lock()
+			if done {
+				subscriberMux.Unlock()
+				onNextDropped(t)
 				return
 			}
 
-			hasItems = true
-			i := currentIndex
-			currentIndex++
-			openSubscriptions++
-			mux.Unlock()
-
-			sub := f(t).Subscribe(DoObserver(
-				func(t cesium.T) {
-					mux.Lock()
-					if !closed {
-						emissionBuffer = append(emissionBuffer, indexedEmission{t, i})
-						if requested > 0 {
-							requested--
-							emission := emissionBuffer[0]
-							emissionBuffer = emissionBuffer[1:]
-							subscriptions[i].Request(1)
-							mux.Unlock()
+			emitted++
+			subscriber.OnNext(t)
+			if emitted < n {
+				subscriberMux.Unlock()
+				return
+			}
 
-							scheduler.Schedule(func(c cesium.Canceller) {
-								if !c.IsCancelled() {
-									subscriberMux.Lock()
-									subscriber.OnNext(emission.t)
-									subscriberMux.Unlock()
-								}
-							})
+			done = true
+			subscriber.OnComplete()
+			subscriberMux.Unlock()
 
-							return
-						}
+			subscriptionMux.Lock()
+			subscription.Cancel()
+			subscriptionMux.Unlock()
+		},
+		onComplete: func() {
+			subscriberMux.Lock()
+			if !done {
+				done = true
+				subscriber.OnComplete()
+			}
+			subscriberMux.Unlock()
+		},
+		onError: func(err error) {
+			subscriberMux.Lock()
+			if !done {
+				done = true
+				subscriber.OnError(err)
+			}
+			subscriberMux.Unlock()
+		},
+	}
+
+	return p
+}
+
+type indexedEmission struct {
+	t     cesium.T
+	index int
+}
+
+func FlatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Scheduler) cesium.Processor {
+	return flatMapProcessor(f, scheduler, false)
+}
+
+// FlatMapDelayErrorProcessor works like FlatMapProcessor, but an error from
+// the main or one of the inner publishers does not terminate the sequence. The
+// remaining publishers are consumed and all the collected errors are emitted
+// as a cesium.CompositeError in the end.
+func FlatMapDelayErrorProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Scheduler) cesium.Processor {
+	return flatMapProcessor(f, scheduler, true)
+}
+
+func flatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Scheduler, delayError bool) cesium.Processor {
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	var emissionBuffer []indexedEmission
+	subscriptions := make(map[int]cesium.Subscription)
+	mux := sync.Mutex{}
+	closed := false
+	currentIndex := 0
+	openSubscriptions := 0
+	requested := int64(0)
+	mainEmittedAll := false
+	var errs []error
+
+	terminate := func(errs []error) {
+		if len(errs) > 0 {
+			subscriber.OnError(&cesium.CompositeError{Errors: errs})
+			return
+		}
+
+		subscriber.OnComplete()
+	}
+
+	return &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
+					subscriptionMux.Lock()
+					if subscription != nil {
+						subscription.Cancel()
+					}
+					subscriptionMux.Unlock()
+				},
+				RequestFunc: func(n int64) {
+					subscriptionMux.Lock()
+					mux.Lock()
+					for len(emissionBuffer) > 0 && n > 0 {
+						emission := emissionBuffer[0]
+						emissionBuffer = emissionBuffer[1:]
+						n--
+
+						scheduler.Schedule(func(c cesium.Canceller) {
+							if !c.IsCancelled() {
+								subscriber.OnNext(emission.t)
+								mux.Lock()
+								subscriptions[emission.index].Request(1)
+								mux.Unlock()
+							}
+						})
+					}
+
+					if openSubscriptions == 0 && mainEmittedAll && len(emissionBuffer) == 0 {
+						collected := errs
+						scheduler.Schedule(func(c cesium.Canceller) {
+							terminate(collected)
+						})
+					}
+
+					addDemandAtomic(&requested, n)
+					mux.Unlock()
+					subscriptionMux.Unlock()
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			s.Request(math.MaxInt64)
+			subscriptionMux.Unlock()
+		},
+		onNext: func(t cesium.T) {
+			mux.Lock()
+			if closed {
+				mux.Unlock()
+				onNextDropped(t)
+				return
+			}
+
+			i := currentIndex
+			currentIndex++
+			openSubscriptions++
+			mux.Unlock()
+
+			sub := FluxFrom(f(t)).Subscribe(DoObserver(
+				func(t cesium.T) {
+					mux.Lock()
+					if !closed {
+						emissionBuffer = append(emissionBuffer, indexedEmission{t, i})
+						if atomic.LoadInt64(&requested) > 0 {
+							produceDemand(&requested, 1)
+							emission := emissionBuffer[0]
+							emissionBuffer = emissionBuffer[1:]
+							subscriptions[i].Request(1)
+							mux.Unlock()
+
+							scheduler.Schedule(func(c cesium.Canceller) {
+								if !c.IsCancelled() {
+									subscriberMux.Lock()
+									subscriber.OnNext(emission.t)
+									subscriberMux.Unlock()
+								}
+							})
+
+							return
+						}
 
 						mux.Unlock()
 						return
@@ -1241,9 +1773,10 @@ func FlatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Schedu
 					mux.Lock()
 					openSubscriptions--
 					if openSubscriptions == 0 && mainEmittedAll && len(emissionBuffer) == 0 {
+						collected := errs
 						scheduler.Schedule(func(c cesium.Canceller) {
 							if !c.IsCancelled() {
-								subscriber.OnComplete()
+								terminate(collected)
 							}
 						})
 
@@ -1252,6 +1785,21 @@ func FlatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Schedu
 				},
 				func(err error) {
 					mux.Lock()
+					if delayError {
+						errs = append(errs, err)
+						openSubscriptions--
+						if openSubscriptions == 0 && mainEmittedAll && len(emissionBuffer) == 0 {
+							collected := errs
+							scheduler.Schedule(func(c cesium.Canceller) {
+								if !c.IsCancelled() {
+									terminate(collected)
+								}
+							})
+						}
+						mux.Unlock()
+						return
+					}
+
 					closed = true
 					emissionBuffer = []indexedEmission{}
 
@@ -1274,16 +1822,34 @@ func FlatMapProcessor(f func(cesium.T) cesium.Publisher, scheduler cesium.Schedu
 		onComplete: func() {
 			mux.Lock()
 			mainEmittedAll = true
+			done := openSubscriptions == 0 && len(emissionBuffer) == 0
+			collected := errs
 			mux.Unlock()
 
-			subscriberMux.Lock()
-			if !hasItems {
-				subscriber.OnComplete()
+			if done {
+				subscriberMux.Lock()
+				terminate(collected)
+				subscriberMux.Unlock()
 			}
-			subscriberMux.Unlock()
 		},
 		onError: func(err error) {
 			mux.Lock()
+			if delayError {
+				errs = append(errs, err)
+				mainEmittedAll = true
+				if openSubscriptions == 0 && len(emissionBuffer) == 0 {
+					collected := errs
+					mux.Unlock()
+
+					subscriberMux.Lock()
+					terminate(collected)
+					subscriberMux.Unlock()
+					return
+				}
+				mux.Unlock()
+				return
+			}
+
 			closed = true
 			emissionBuffer = []indexedEmission{}
 
@@ -1305,7 +1871,8 @@ func OnErrorReturnProcessor(fallbackValue cesium.T) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -1316,11 +1883,7 @@ func OnErrorReturnProcessor(fallbackValue cesium.T) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1353,6 +1916,8 @@ func OnErrorReturnProcessor(fallbackValue cesium.T) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func DoOnEachProcessor(f func(cesium.Signal)) cesium.Processor {
@@ -1361,7 +1926,8 @@ func DoOnEachProcessor(f func(cesium.Signal)) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -1372,11 +1938,7 @@ func DoOnEachProcessor(f func(cesium.Signal)) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1412,6 +1974,8 @@ func DoOnEachProcessor(f func(cesium.Signal)) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func MaterializeProcessor() cesium.Processor {
@@ -1420,7 +1984,8 @@ func MaterializeProcessor() cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -1431,11 +1996,7 @@ func MaterializeProcessor() cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1469,6 +2030,8 @@ func MaterializeProcessor() cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func DematerializeProcessor() cesium.Processor {
@@ -1479,7 +2042,8 @@ func DematerializeProcessor() cesium.Processor {
 
 	completed := false
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -1490,11 +2054,7 @@ func DematerializeProcessor() cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(n)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1541,6 +2101,8 @@ func DematerializeProcessor() cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
 }
 
 func OnErrorResumeProcessor(predicate func(error) bool, publisher cesium.Publisher) cesium.Processor {
@@ -1549,8 +2111,7 @@ func OnErrorResumeProcessor(predicate func(error) bool, publisher cesium.Publish
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	pendingRequests := int64(0)
-	unbounded := false
+	requested := int64(0)
 	recovered := false
 	var p *processor
 
@@ -1566,20 +2127,16 @@ func OnErrorResumeProcessor(predicate func(error) bool, publisher cesium.Publish
 				},
 				RequestFunc: func(n int64) {
 					subscriptionMux.Lock()
-					if unbounded {
+					if addDemandAtomic(&requested, n) == math.MaxInt64 {
+						subscriptionMux.Unlock()
 						return
 					}
+					s := subscription
+					subscriptionMux.Unlock()
 
-					if n == math.MaxInt64 {
-						unbounded = true
-					} else {
-						pendingRequests = pendingRequests + n
-					}
-
-					if subscription != nil {
-						subscription.Request(n)
+					if s != nil {
+						s.Request(n)
 					}
-					subscriptionMux.Unlock()
 				},
 			}
 
@@ -1591,16 +2148,21 @@ func OnErrorResumeProcessor(predicate func(error) bool, publisher cesium.Publish
 			return sub
 		},
 		onSubscribe: func(s cesium.Subscription) {
+			// The resumed publisher is requested the outstanding demand, read
+			// together with the subscription swap, so that a concurrent
+			// request is passed to it exactly once.
 			subscriptionMux.Lock()
 			subscription = s
+			n := atomic.LoadInt64(&requested)
 			subscriptionMux.Unlock()
+
+			if s != nil && n > 0 {
+				s.Request(n)
+			}
 		},
 		onNext: func(t cesium.T) {
 			subscriberMux.Lock()
-			subscriptionMux.Lock()
-			pendingRequests--
-			subscriptionMux.Unlock()
-
+			produceDemand(&requested, 1)
 			subscriber.OnNext(t)
 			subscriberMux.Unlock()
 		},
@@ -1614,11 +2176,11 @@ func OnErrorResumeProcessor(predicate func(error) bool, publisher cesium.Publish
 			if !recovered && predicate(err) {
 				recovered = true
 				subscriptionMux.Lock()
-				subscription.Cancel()
+				s := subscription
 				subscriptionMux.Unlock()
+				s.Cancel()
 
-				publisher.Subscribe(p).Request(pendingRequests)
-
+				FluxFrom(publisher).Subscribe(p)
 				subscriberMux.Unlock()
 				return
 			}
@@ -1637,7 +2199,8 @@ func OnErrorMapProcessor(f func(error) error) cesium.Processor {
 	subscriberMux := sync.Mutex{}
 	subscriptionMux := sync.Mutex{}
 
-	return &processor{
+	var p *processor
+	p = &processor{
 		subscribe: func(s cesium.Subscriber) cesium.Subscription {
 			sub := &Subscription{
 				CancelFunc: func() {
@@ -1648,11 +2211,7 @@ func OnErrorMapProcessor(f func(error) error) cesium.Processor {
 					subscriptionMux.Unlock()
 				},
 				RequestFunc: func(n int64) {
-					subscriptionMux.Lock()
-					if subscription != nil {
-						subscription.Request(1)
-					}
-					subscriptionMux.Unlock()
+					p.request(n)
 				},
 			}
 
@@ -1684,4 +2243,598 @@ func OnErrorMapProcessor(f func(error) error) cesium.Processor {
 			subscriberMux.Unlock()
 		},
 	}
+
+	return p
+}
+
+type filterWhenSlot struct {
+	item         cesium.T
+	resolved     bool
+	passed       bool
+	subscription cesium.Subscription
+}
+
+func FilterWhenProcessor(f func(cesium.T) cesium.Publisher, bufferSize int) cesium.Processor {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	mux := sync.Mutex{}
+	var slots []*filterWhenSlot
+	requested := int64(0)
+	mainCompleted := false
+	terminated := false
+	draining := false
+	missed := false
+
+	cancelAll := func() {
+		subscriptionMux.Lock()
+		if subscription != nil {
+			subscription.Cancel()
+		}
+		subscriptionMux.Unlock()
+
+		mux.Lock()
+		pending := slots
+		slots = nil
+		mux.Unlock()
+
+		for _, slot := range pending {
+			if slot.subscription != nil {
+				slot.subscription.Cancel()
+			}
+		}
+	}
+
+	// drain emits the resolved items in the order they were received from
+	// upstream. Only one goroutine drains at a time, the others just mark
+	// that there is more work to do.
+	drain := func() {
+		mux.Lock()
+		if draining {
+			missed = true
+			mux.Unlock()
+			return
+		}
+		draining = true
+
+		for {
+			missed = false
+			consumed := int64(0)
+
+			for !terminated && len(slots) > 0 && slots[0].resolved {
+				slot := slots[0]
+				if slot.passed {
+					if atomic.LoadInt64(&requested) == 0 {
+						break
+					}
+					produceDemand(&requested, 1)
+				}
+
+				slots = slots[1:]
+				consumed++
+
+				if slot.passed {
+					mux.Unlock()
+					subscriberMux.Lock()
+					subscriber.OnNext(slot.item)
+					subscriberMux.Unlock()
+					mux.Lock()
+				}
+			}
+
+			complete := !terminated && mainCompleted && len(slots) == 0
+			if complete {
+				terminated = true
+			}
+			mux.Unlock()
+
+			if complete {
+				subscriberMux.Lock()
+				subscriber.OnComplete()
+				subscriberMux.Unlock()
+			} else if consumed > 0 {
+				subscriptionMux.Lock()
+				s := subscription
+				subscriptionMux.Unlock()
+				s.Request(consumed)
+			}
+
+			mux.Lock()
+			if !missed {
+				draining = false
+				mux.Unlock()
+				return
+			}
+		}
+	}
+
+	fail := func(err error) {
+		mux.Lock()
+		if terminated {
+			mux.Unlock()
+			return
+		}
+		terminated = true
+		mux.Unlock()
+
+		cancelAll()
+
+		subscriberMux.Lock()
+		subscriber.OnError(err)
+		subscriberMux.Unlock()
+	}
+
+	return &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
+					mux.Lock()
+					terminated = true
+					mux.Unlock()
+
+					cancelAll()
+				},
+				RequestFunc: func(n int64) {
+					addDemandAtomic(&requested, n)
+					drain()
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			subscriptionMux.Unlock()
+
+			s.Request(int64(bufferSize))
+		},
+		onNext: func(t cesium.T) {
+			slot := &filterWhenSlot{item: t}
+
+			mux.Lock()
+			if terminated {
+				mux.Unlock()
+				return
+			}
+			slots = append(slots, slot)
+			mux.Unlock()
+
+			resolve := func(passed bool) {
+				mux.Lock()
+				if slot.resolved {
+					mux.Unlock()
+					return
+				}
+				slot.resolved = true
+				slot.passed = passed
+				mux.Unlock()
+
+				drain()
+			}
+
+			sub := FluxFrom(f(t)).Subscribe(DoObserver(
+				func(result cesium.T) {
+					b, ok := result.(bool)
+					resolve(ok && b)
+
+					mux.Lock()
+					s := slot.subscription
+					mux.Unlock()
+					if s != nil {
+						s.Cancel()
+					}
+				},
+				func() {
+					resolve(false)
+				},
+				func(err error) {
+					fail(err)
+				},
+			))
+
+			mux.Lock()
+			slot.subscription = sub
+			mux.Unlock()
+
+			sub.Request(1)
+		},
+		onComplete: func() {
+			mux.Lock()
+			mainCompleted = true
+			mux.Unlock()
+
+			drain()
+		},
+		onError: func(err error) {
+			fail(err)
+		},
+	}
+}
+
+func DelayElementsProcessor(delay time.Duration, scheduler cesium.TimedScheduler) cesium.Processor {
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	mux := sync.Mutex{}
+	var queue []cesium.T
+	var timer cesium.Cancellable
+	delaying := false
+	completed := false
+	terminated := false
+
+	// delayNext waits for the delay before emitting the head of the queue, so
+	// that the emissions are spaced by at least the delay.
+	var delayNext func()
+	delayNext = func() {
+		delaying = true
+		timer = scheduler.ScheduleAfter(delay, func(c cesium.Canceller) {
+			mux.Lock()
+			if c.IsCancelled() || terminated {
+				mux.Unlock()
+				return
+			}
+
+			t := queue[0]
+			queue = queue[1:]
+			delaying = false
+			if len(queue) > 0 {
+				delayNext()
+			}
+			complete := completed && len(queue) == 0
+			if complete {
+				terminated = true
+			}
+			mux.Unlock()
+
+			subscriberMux.Lock()
+			subscriber.OnNext(t)
+			if complete {
+				subscriber.OnComplete()
+			}
+			subscriberMux.Unlock()
+		})
+	}
+
+	var p *processor
+	p = &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
+					mux.Lock()
+					terminated = true
+					if timer != nil {
+						timer.Cancel()
+					}
+					mux.Unlock()
+
+					subscriptionMux.Lock()
+					if subscription != nil {
+						subscription.Cancel()
+					}
+					subscriptionMux.Unlock()
+				},
+				RequestFunc: func(n int64) {
+					p.request(n)
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			subscriptionMux.Unlock()
+		},
+		onNext: func(t cesium.T) {
+			mux.Lock()
+			if terminated {
+				mux.Unlock()
+				onNextDropped(t)
+				return
+			}
+
+			queue = append(queue, t)
+			if !delaying {
+				delayNext()
+			}
+			mux.Unlock()
+		},
+		onComplete: func() {
+			mux.Lock()
+			if terminated {
+				mux.Unlock()
+				return
+			}
+
+			completed = true
+			complete := len(queue) == 0 && !delaying
+			if complete {
+				terminated = true
+			}
+			mux.Unlock()
+
+			if complete {
+				subscriberMux.Lock()
+				subscriber.OnComplete()
+				subscriberMux.Unlock()
+			}
+		},
+		onError: func(err error) {
+			mux.Lock()
+			if terminated {
+				mux.Unlock()
+				onErrorDropped(err)
+				return
+			}
+
+			terminated = true
+			if timer != nil {
+				timer.Cancel()
+			}
+			mux.Unlock()
+
+			subscriberMux.Lock()
+			subscriber.OnError(err)
+			subscriberMux.Unlock()
+		},
+	}
+
+	return p
+}
+
+func DelayUntilProcessor(f func(cesium.T) cesium.Publisher) cesium.Processor {
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	mux := sync.Mutex{}
+	var triggerSubscription cesium.Subscription
+	var item cesium.T
+	hasItem := false
+	delaying := false
+	completed := false
+	terminated := false
+
+	var p *processor
+	p = &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
+					mux.Lock()
+					terminated = true
+					if triggerSubscription != nil {
+						triggerSubscription.Cancel()
+					}
+					mux.Unlock()
+
+					subscriptionMux.Lock()
+					if subscription != nil {
+						subscription.Cancel()
+					}
+					subscriptionMux.Unlock()
+				},
+				RequestFunc: func(n int64) {
+					p.request(n)
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			subscriptionMux.Unlock()
+		},
+		onNext: func(t cesium.T) {
+			mux.Lock()
+			if terminated || hasItem {
+				mux.Unlock()
+				return
+			}
+
+			item = t
+			hasItem = true
+			delaying = true
+			mux.Unlock()
+
+			ts := FluxFrom(f(t)).Subscribe(DoObserver(
+				func(cesium.T) {},
+				func() {
+					mux.Lock()
+					if terminated {
+						mux.Unlock()
+						return
+					}
+
+					delaying = false
+					complete := completed
+					if complete {
+						terminated = true
+					}
+					mux.Unlock()
+
+					subscriberMux.Lock()
+					subscriber.OnNext(item)
+					if complete {
+						subscriber.OnComplete()
+					}
+					subscriberMux.Unlock()
+				},
+				func(err error) {
+					mux.Lock()
+					if terminated {
+						mux.Unlock()
+						return
+					}
+					terminated = true
+					mux.Unlock()
+
+					subscriptionMux.Lock()
+					subscription.Cancel()
+					subscriptionMux.Unlock()
+
+					subscriberMux.Lock()
+					subscriber.OnError(err)
+					subscriberMux.Unlock()
+				},
+			))
+
+			mux.Lock()
+			triggerSubscription = ts
+			mux.Unlock()
+
+			ts.RequestUnbounded()
+		},
+		onComplete: func() {
+			mux.Lock()
+			if terminated {
+				mux.Unlock()
+				return
+			}
+
+			completed = true
+			complete := !delaying
+			if complete {
+				terminated = true
+			}
+			mux.Unlock()
+
+			if complete {
+				subscriberMux.Lock()
+				subscriber.OnComplete()
+				subscriberMux.Unlock()
+			}
+		},
+		onError: func(err error) {
+			mux.Lock()
+			if terminated {
+				mux.Unlock()
+				onErrorDropped(err)
+				return
+			}
+
+			terminated = true
+			if triggerSubscription != nil {
+				triggerSubscription.Cancel()
+			}
+			mux.Unlock()
+
+			subscriberMux.Lock()
+			subscriber.OnError(err)
+			subscriberMux.Unlock()
+		},
+	}
+
+	return p
+}
+
+func TimestampProcessor(clock cesium.Clock) cesium.Processor {
+	return MapProcessor(func(t cesium.T) cesium.T {
+		return cesium.TimestampedItem{Timestamp: clock.Now(), Item: t}
+	})
+}
+
+// ElapsedProcessor measures the time elapsed between emissions. The time of
+// the first emission is measured from the creation of the processor, which
+// happens upon subscription.
+func ElapsedProcessor(clock cesium.Clock) cesium.Processor {
+	last := clock.Now()
+
+	return MapProcessor(func(t cesium.T) cesium.T {
+		now := clock.Now()
+		elapsed := now.Sub(last)
+		last = now
+
+		return cesium.ElapsedItem{Elapsed: elapsed, Item: t}
+	})
+}
+
+func CollectProcessor(supplier func() cesium.T, accumulator func(cesium.T, cesium.T), finisher func(cesium.T) cesium.T) cesium.Processor {
+	var subscriber cesium.Subscriber
+	var subscription cesium.Subscription
+	subscriberMux := sync.Mutex{}
+	subscriptionMux := sync.Mutex{}
+
+	container := supplier()
+	requested := false
+	mux := sync.Mutex{}
+
+	return &processor{
+		subscribe: func(s cesium.Subscriber) cesium.Subscription {
+			sub := &Subscription{
+				CancelFunc: func() {
+					subscriptionMux.Lock()
+					if subscription != nil {
+						subscription.Cancel()
+					}
+					subscriptionMux.Unlock()
+				},
+				RequestFunc: func(n int64) {
+					subscriptionMux.Lock()
+					if subscription != nil && !requested {
+						requested = true
+						subscription.RequestUnbounded()
+					}
+					subscriptionMux.Unlock()
+				},
+			}
+
+			subscriberMux.Lock()
+			subscriber = s
+			subscriber.OnSubscribe(subscription)
+			subscriberMux.Unlock()
+
+			return sub
+		},
+		onSubscribe: func(s cesium.Subscription) {
+			subscriptionMux.Lock()
+			subscription = s
+			subscriptionMux.Unlock()
+		},
+		onNext: func(t cesium.T) {
+			mux.Lock()
+			accumulator(container, t)
+			mux.Unlock()
+		},
+		onComplete: func() {
+			mux.Lock()
+			result := finisher(container)
+			mux.Unlock()
+
+			subscriberMux.Lock()
+			subscriber.OnNext(result)
+			subscriber.OnComplete()
+			subscriberMux.Unlock()
+		},
+		onError: func(err error) {
+			subscriberMux.Lock()
+			subscriber.OnError(err)
+			subscriberMux.Unlock()
+		},
+	}
 }
@@ -1,7 +1,9 @@
 package internal
 
 import (
+	"runtime"
 	"sync"
+	"time"
 
 	"github.com/DusanKasan/cesium"
 )
@@ -74,3 +76,150 @@ func SeparateGoroutineScheduler() cesium.Scheduler {
 		},
 	}
 }
+
+// ImmediateScheduler returns a cesium.Scheduler that executes the actions
+// right away, on the goroutine scheduling them.
+func ImmediateScheduler() cesium.Scheduler {
+	return &internalScheduler{
+		schedule: func(action func(cesium.Canceller)) cesium.Cancellable {
+			cc := &canceller{}
+			action(cc)
+
+			return &cancellable{
+				func() {
+					cc.Cancel()
+				},
+			}
+		},
+	}
+}
+
+type timedScheduler struct {
+	cesium.Scheduler
+}
+
+func (ts *timedScheduler) Now() time.Time {
+	return time.Now()
+}
+
+func (ts *timedScheduler) ScheduleAfter(delay time.Duration, action func(cesium.Canceller)) cesium.Cancellable {
+	cc := &canceller{}
+
+	timer := time.AfterFunc(delay, func() {
+		if !cc.IsCancelled() {
+			action(cc)
+		}
+	})
+
+	return &cancellable{
+		func() {
+			timer.Stop()
+			cc.Cancel()
+		},
+	}
+}
+
+// TimerScheduler returns a cesium.TimedScheduler that executes the delayed
+// actions using the runtime timers, each on its own goroutine.
+func TimerScheduler() cesium.TimedScheduler {
+	return &timedScheduler{SeparateGoroutineScheduler()}
+}
+
+// workerPool executes the scheduled actions on a fixed number of goroutines.
+// The actions are queued until one of the workers is free. Once disposed, the
+// queued actions are dropped and the workers exit.
+type workerPool struct {
+	mux      sync.Mutex
+	cond     *sync.Cond
+	actions  []func()
+	disposed bool
+}
+
+func (p *workerPool) work() {
+	for {
+		p.mux.Lock()
+		for len(p.actions) == 0 && !p.disposed {
+			p.cond.Wait()
+		}
+		if p.disposed {
+			p.mux.Unlock()
+			return
+		}
+		action := p.actions[0]
+		p.actions[0] = nil
+		p.actions = p.actions[1:]
+		p.mux.Unlock()
+
+		action()
+	}
+}
+
+func (p *workerPool) Schedule(action func(cesium.Canceller)) cesium.Cancellable {
+	cc := &canceller{}
+
+	p.mux.Lock()
+	if p.disposed {
+		p.mux.Unlock()
+		cc.Cancel()
+		return &cancellable{cc.Cancel}
+	}
+	p.actions = append(p.actions, func() {
+		if !cc.IsCancelled() {
+			action(cc)
+		}
+	})
+	p.mux.Unlock()
+	p.cond.Signal()
+
+	return &cancellable{
+		func() {
+			cc.Cancel()
+		},
+	}
+}
+
+func (p *workerPool) Dispose() {
+	p.mux.Lock()
+	p.disposed = true
+	p.actions = nil
+	p.mux.Unlock()
+	p.cond.Broadcast()
+}
+
+func (p *workerPool) IsDisposed() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.disposed
+}
+
+// WorkerPoolScheduler returns a cesium.DisposableScheduler that executes the
+// actions on the specified number of goroutines (runtime.NumCPU() if workers
+// is not positive), so at most that many actions run in parallel. The
+// goroutines run until the scheduler is disposed.
+func WorkerPoolScheduler(workers int) cesium.DisposableScheduler {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	pool := &workerPool{}
+	pool.cond = sync.NewCond(&pool.mux)
+	for i := 0; i < workers; i++ {
+		go pool.work()
+	}
+
+	return pool
+}
+
+var parallelScheduler cesium.Scheduler
+var parallelSchedulerOnce sync.Once
+
+// ParallelScheduler returns the shared worker pool scheduler with
+// runtime.NumCPU() workers. It is never disposed.
+func ParallelScheduler() cesium.Scheduler {
+	parallelSchedulerOnce.Do(func() {
+		parallelScheduler = WorkerPoolScheduler(0)
+	})
+
+	return parallelScheduler
+}
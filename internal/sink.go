@@ -2,8 +2,7 @@ package internal
 
 import (
 	"sync"
-
-	"math"
+	"sync/atomic"
 
 	"github.com/DusanKasan/cesium"
 )
@@ -112,7 +111,14 @@ func (f *FluxSink) OnRequest(fn func(n int64)) {
 	f.onRequestMux.Unlock()
 }
 
+// Request adds to the demand of the subscriber. Non-positive requests are
+// ignored, they are reported to the subscriber when made through its
+// subscription.
 func (f *FluxSink) Request(n int64) {
+	if n <= 0 {
+		return
+	}
+
 	f.cancelerMux.Lock()
 	if f.canceller.IsCancelled() {
 		f.cancelerMux.Unlock()
@@ -124,7 +130,7 @@ func (f *FluxSink) Request(n int64) {
 	if f.onRequest != nil {
 		f.onRequest(n)
 	}
-	f.recordedNumberOfRequests = f.recordedNumberOfRequests + n
+	f.recordedNumberOfRequests = addDemand(f.recordedNumberOfRequests, n)
 	f.onRequestMux.Unlock()
 
 	f.internalRequest(n)
@@ -137,12 +143,25 @@ func (f *FluxSink) RequestedFromDownstream() int64 {
 func BufferFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 	scheduler := SeparateGoroutineScheduler()
 
+	// The demand is only consumed while holding the buffer lock, so that an
+	// item is either emitted or buffered before a request flushes the buffer.
 	bufferMux := sync.Mutex{}
 	buffer := []cesium.Signal{}
-
-	requestedMux := sync.Mutex{}
 	requested := int64(0)
-	unbounded := false
+
+	// terminate emits the terminal signal once the buffered items are
+	// emitted, regardless of the demand.
+	terminate := func(sig cesium.Signal) {
+		bufferMux.Lock()
+		if len(buffer) == 0 {
+			scheduler.Schedule(func(c cesium.Canceller) {
+				sig.Accept(s)
+			})
+		} else {
+			buffer = append(buffer, sig)
+		}
+		bufferMux.Unlock()
+	}
 
 	fs := &FluxSink{
 		next: func(t cesium.T) {
@@ -150,26 +169,16 @@ func BufferFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 				return
 			}
 
-			requestedMux.Lock()
-			if unbounded {
-				requestedMux.Unlock()
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnNext(t)
-				})
-				return
-			}
-
-			if requested > 0 {
-				requested = requested - 1
-				requestedMux.Unlock()
+			bufferMux.Lock()
+			if len(buffer) == 0 && atomic.LoadInt64(&requested) > 0 {
+				produceDemand(&requested, 1)
+				bufferMux.Unlock()
 				scheduler.Schedule(func(c cesium.Canceller) {
 					s.OnNext(t)
 				})
 				return
 			}
-			requestedMux.Unlock()
 
-			bufferMux.Lock()
 			buffer = append(buffer, NextSignal(t))
 			bufferMux.Unlock()
 		},
@@ -178,122 +187,37 @@ func BufferFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 				return
 			}
 
-			requestedMux.Lock()
-			if unbounded {
-				requestedMux.Unlock()
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnComplete()
-				})
-				return
-			}
-
-			if requested > 0 {
-				requested = requested - 1
-				requestedMux.Unlock()
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnComplete()
-				})
-				return
-			}
-			requestedMux.Unlock()
-
-			bufferMux.Lock()
-			if len(buffer) == 0 {
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnComplete()
-				})
-			} else {
-				buffer = append(buffer, CompleteSignal())
-			}
-			bufferMux.Unlock()
+			terminate(CompleteSignal())
 		},
 		error: func(err error) {
 			if c.IsCancelled() {
 				return
 			}
 
-			requestedMux.Lock()
-			if unbounded {
-				requestedMux.Unlock()
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnError(err)
-				})
-				return
-			}
-
-			if requested > 0 {
-				requested = requested - 1
-				requestedMux.Unlock()
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnError(err)
-				})
-				return
-			}
-			requestedMux.Unlock()
-
-			bufferMux.Lock()
-			if len(buffer) == 0 {
-				scheduler.Schedule(func(c cesium.Canceller) {
-					s.OnError(err)
-				})
-			} else {
-				buffer = append(buffer, ErrorSignal(err))
-			}
-			bufferMux.Unlock()
+			terminate(ErrorSignal(err))
 		},
 		internalRequest: func(n int64) {
 			if c.IsCancelled() {
 				return
 			}
 
-			requestedMux.Lock()
-			if unbounded {
-				return
-			}
-
-			if n == math.MaxInt64 {
-				unbounded = true
-			}
-
-			if unbounded {
-				bufferMux.Lock()
-				for len(buffer) > 0 {
-					sig := buffer[0]
-					buffer = buffer[1:]
-
-					scheduler.Schedule(func(c cesium.Canceller) {
-						sig.Accept(s)
-					})
-				}
-				bufferMux.Unlock()
-				requestedMux.Unlock()
-				return
-			}
-
-			requested = requested + n
 			bufferMux.Lock()
-			for len(buffer) > 0 && requested > 0 {
+			addDemandAtomic(&requested, n)
+			for len(buffer) > 0 {
 				sig := buffer[0]
+				if !sig.IsTerminal() {
+					if atomic.LoadInt64(&requested) == 0 {
+						break
+					}
+					produceDemand(&requested, 1)
+				}
 				buffer = buffer[1:]
-				requested = requested - 1
 
 				scheduler.Schedule(func(c cesium.Canceller) {
 					sig.Accept(s)
 				})
 			}
-
-			if len(buffer) == 1 {
-				sig := buffer[0]
-
-				if sig.IsTerminal() {
-					scheduler.Schedule(func(c cesium.Canceller) {
-						sig.Accept(s)
-					})
-					buffer = []cesium.Signal{}
-				}
-			}
 			bufferMux.Unlock()
-			requestedMux.Unlock()
 		},
 		canceller: c,
 	}
@@ -304,9 +228,7 @@ func BufferFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 }
 
 func DropFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
-	requestedMux := sync.Mutex{}
 	requested := int64(0)
-	unbounded := false
 
 	fs := &FluxSink{
 		next: func(t cesium.T) {
@@ -314,20 +236,13 @@ func DropFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 				return
 			}
 
-			requestedMux.Lock()
-			if unbounded {
-				requestedMux.Unlock()
+			if atomic.LoadInt64(&requested) > 0 {
+				produceDemand(&requested, 1)
 				s.OnNext(t)
 				return
 			}
 
-			if requested > 0 {
-				requested = requested - 1
-				requestedMux.Unlock()
-				s.OnNext(t)
-				return
-			}
-			requestedMux.Unlock()
+			onNextDropped(t)
 		},
 		complete: func() {
 			if c.IsCancelled() {
@@ -348,15 +263,7 @@ func DropFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 				return
 			}
 
-			requestedMux.Lock()
-			if n == math.MaxInt64 {
-				unbounded = true
-				requestedMux.Unlock()
-				return
-			}
-
-			requested = requested + n
-			requestedMux.Unlock()
+			addDemandAtomic(&requested, n)
 		},
 		canceller: c,
 	}
@@ -367,12 +274,10 @@ func DropFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 }
 
 func ErrorFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
-	requestedMux := sync.Mutex{}
 	requested := int64(0)
 
 	closed := false
 	closedMux := sync.Mutex{}
-	unbounded := false
 
 	fs := &FluxSink{
 		next: func(t cesium.T) {
@@ -383,28 +288,21 @@ func ErrorFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 			closedMux.Lock()
 			if closed {
 				closedMux.Unlock()
+				onNextDropped(t)
 				return
 			}
 			closedMux.Unlock()
 
-			requestedMux.Lock()
-			if unbounded {
-				requestedMux.Unlock()
+			if atomic.LoadInt64(&requested) > 0 {
+				produceDemand(&requested, 1)
 				s.OnNext(t)
 				return
 			}
 
-			if requested > 0 {
-				requested = requested - 1
-				requestedMux.Unlock()
-				s.OnNext(t)
-				return
-			}
-			requestedMux.Unlock()
-
 			closedMux.Lock()
 			closed = true
 			closedMux.Unlock()
+			onNextDropped(t)
 			s.OnError(cesium.DownstreamUnableToKeepUpError)
 		},
 		complete: func() {
@@ -429,34 +327,24 @@ func ErrorFluxSink(s cesium.Subscriber, c cesium.Canceller) *FluxSink {
 			closedMux.Lock()
 			if closed {
 				closedMux.Unlock()
+				onErrorDropped(err)
 				return
 			}
 			closedMux.Unlock()
 
-			requestedMux.Lock()
-			if requested > 0 {
-				requested = requested - 1
-				requestedMux.Unlock()
+			if atomic.LoadInt64(&requested) > 0 {
 				s.OnError(err)
 				return
 			}
-			requestedMux.Unlock()
 
+			onErrorDropped(err)
 		},
 		internalRequest: func(n int64) {
 			if c.IsCancelled() {
 				return
 			}
 
-			requestedMux.Lock()
-			if n == math.MaxInt64 {
-				unbounded = true
-				requestedMux.Unlock()
-				return
-			}
-
-			requested = requested + n
-			requestedMux.Unlock()
+			addDemandAtomic(&requested, n)
 		},
 		canceller: c,
 	}
@@ -509,6 +397,7 @@ func (s *SynchronousSink) Next(t cesium.T) {
 	s.emittedMux.Lock()
 	if s.emitted {
 		s.emittedMux.Unlock()
+		onNextDropped(t)
 		return
 	}
 	s.emitted = true
@@ -533,6 +422,7 @@ func (s *SynchronousSink) Error(err error) {
 	s.emittedMux.Lock()
 	if s.emitted {
 		s.emittedMux.Unlock()
+		onErrorDropped(err)
 		return
 	}
 	s.emitted = true
@@ -566,6 +456,7 @@ func (f *MonoSink) CompleteWith(t cesium.T) {
 	f.closedMux.Lock()
 	if f.closed {
 		f.closedMux.Unlock()
+		onNextDropped(t)
 		return
 	}
 	f.closed = true
@@ -602,6 +493,7 @@ func (f *MonoSink) Error(err error) {
 	f.closedMux.Lock()
 	if f.closed {
 		f.closedMux.Unlock()
+		onErrorDropped(err)
 		return
 	}
 	f.closed = true
@@ -662,7 +554,13 @@ func (f *MonoSink) Dispose() {
 	f.onDisposeMux.Unlock()
 }
 
+// Request adds to the demand of the subscriber. Non-positive requests are
+// ignored, like in FluxSink.Request.
 func (f *MonoSink) Request(n int64) {
+	if n <= 0 {
+		return
+	}
+
 	f.internalRequest(n)
 }
 
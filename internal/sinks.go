@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// serializedEmitter guards a processor against concurrent emissions. Instead
+// of waiting for the emission in progress, the concurrent ones are rejected
+// with cesium.EmitResultFailNonSerialized.
+type serializedEmitter struct {
+	processor *fluxProcessor
+	emitting  int32
+}
+
+func (e *serializedEmitter) emit(f func() cesium.EmitResult) cesium.EmitResult {
+	if !atomic.CompareAndSwapInt32(&e.emitting, 0, 1) {
+		return cesium.EmitResultFailNonSerialized
+	}
+	defer atomic.StoreInt32(&e.emitting, 0)
+
+	return f()
+}
+
+func (e *serializedEmitter) TryEmitComplete() cesium.EmitResult {
+	return e.emit(e.processor.tryComplete)
+}
+
+func (e *serializedEmitter) TryEmitError(err error) cesium.EmitResult {
+	return e.emit(func() cesium.EmitResult {
+		return e.processor.tryError(err)
+	})
+}
+
+type manySink struct {
+	*serializedEmitter
+}
+
+func (s *manySink) TryEmitNext(t cesium.T) cesium.EmitResult {
+	return s.emit(func() cesium.EmitResult {
+		return s.processor.tryNext(t)
+	})
+}
+
+func (s *manySink) AsFlux() cesium.Flux {
+	return s.processor.Flux
+}
+
+type emptySink struct {
+	*serializedEmitter
+}
+
+func (s *emptySink) AsMono() cesium.Mono {
+	return &Mono{OnSubscribe: s.processor.Flux.OnSubscribe}
+}
+
+type oneSink struct {
+	*emptySink
+}
+
+func (s *oneSink) TryEmitNext(t cesium.T) cesium.EmitResult {
+	return s.emit(func() cesium.EmitResult {
+		if result := s.processor.tryNext(t); result != cesium.EmitResultOK {
+			return result
+		}
+
+		return s.processor.tryComplete()
+	})
+}
+
+func newManySink(c processorConnection, maxSubscribers int) *manySink {
+	return &manySink{&serializedEmitter{processor: newFluxProcessor(c, maxSubscribers)}}
+}
+
+// ManyUnicastSink buffers the items until its only subscriber requests them.
+func ManyUnicastSink() cesium.ManySink {
+	return newManySink(&publishConnection{prefetch: publishPrefetch}, 1)
+}
+
+// ManyMulticastSink emits the items to all of its subscribers at the pace of
+// the slowest one.
+func ManyMulticastSink() cesium.ManySink {
+	return newManySink(&publishConnection{prefetch: publishPrefetch}, 0)
+}
+
+// ManyReplaySink replays the last history items to each subscriber.
+func ManyReplaySink(history int) cesium.ManySink {
+	return newManySink(&replayConnection{history: history, clock: TimerScheduler()}, 0)
+}
+
+// OneSink replays its only item or terminal signal to each subscriber.
+func OneSink() cesium.OneSink {
+	c := &replayConnection{history: 1, clock: TimerScheduler()}
+	return &oneSink{&emptySink{&serializedEmitter{processor: newFluxProcessor(c, 0)}}}
+}
+
+// EmptySink replays its terminal signal to each subscriber.
+func EmptySink() cesium.EmptySink {
+	c := &replayConnection{history: 0, clock: TimerScheduler()}
+	return &emptySink{&serializedEmitter{processor: newFluxProcessor(c, 0)}}
+}
@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// specSubscriber enforces the Reactive Streams rules on behalf of a subscriber
+// subscribed through Flux.Subscribe or Mono.Subscribe. A non-positive request
+// (rule 3.9) or a nil item (rule 2.13) cancels the subscription and is
+// reported to the subscriber as cesium.NonPositiveRequestError or
+// cesium.NilItemError. No signals are delivered after a terminal one and the
+// nil subscriptions the operators pass on before subscribing upstream are
+// dropped (rule 1.9).
+type specSubscriber struct {
+	cesium.Subscriber
+
+	mux        sync.Mutex
+	upstream   cesium.Subscription
+	wrapped    *specSubscription
+	emitting   bool
+	terminated bool
+	pending    error
+}
+
+func (s *specSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	if subscription == nil {
+		return
+	}
+
+	s.mux.Lock()
+	s.upstream = subscription
+	s.mux.Unlock()
+
+	s.Subscriber.OnSubscribe(s.wrap(subscription))
+}
+
+func (s *specSubscriber) OnNext(t cesium.T) {
+	if t == nil {
+		s.violate(cesium.NilItemError)
+		return
+	}
+
+	s.mux.Lock()
+	if s.terminated {
+		s.mux.Unlock()
+		onNextDropped(t)
+		return
+	}
+	s.emitting = true
+	s.mux.Unlock()
+
+	s.Subscriber.OnNext(t)
+
+	s.mux.Lock()
+	s.emitting = false
+	err := s.pending
+	s.pending = nil
+	s.mux.Unlock()
+
+	// A violation reported while the item was being emitted, for example by a
+	// request made from the OnNext above.
+	if err != nil {
+		s.Subscriber.OnError(err)
+	}
+}
+
+func (s *specSubscriber) OnComplete() {
+	s.mux.Lock()
+	if s.terminated {
+		s.mux.Unlock()
+		return
+	}
+	s.terminated = true
+	s.mux.Unlock()
+
+	s.Subscriber.OnComplete()
+}
+
+func (s *specSubscriber) OnError(err error) {
+	s.mux.Lock()
+	if s.terminated {
+		s.mux.Unlock()
+		onErrorDropped(err)
+		return
+	}
+	s.terminated = true
+	s.mux.Unlock()
+
+	s.Subscriber.OnError(err)
+}
+
+func (s *specSubscriber) errorContinue() func(error, cesium.T) {
+	return errorContinueOf(s.Subscriber)
+}
+
+// violate cancels the upstream subscription and terminates the subscriber
+// with the error, once the item being emitted (if any) is delivered.
+func (s *specSubscriber) violate(err error) {
+	s.mux.Lock()
+	if s.terminated {
+		s.mux.Unlock()
+		onErrorDropped(err)
+		return
+	}
+	s.terminated = true
+	upstream := s.upstream
+	emitting := s.emitting
+	if emitting {
+		s.pending = err
+	}
+	s.mux.Unlock()
+
+	if upstream != nil {
+		upstream.Cancel()
+	}
+
+	if !emitting {
+		s.Subscriber.OnError(err)
+	}
+}
+
+// wrap guards the subscription, returning the same guard for the subscription
+// handed to the subscriber and the one returned from Subscribe.
+func (s *specSubscriber) wrap(subscription cesium.Subscription) cesium.Subscription {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.wrapped == nil || s.wrapped.subscription != subscription {
+		s.wrapped = &specSubscription{subscription: subscription, subscriber: s}
+	}
+
+	return s.wrapped
+}
+
+type conditionalSpecSubscriber struct {
+	*specSubscriber
+	conditional ConditionalSubscriber
+}
+
+func (s *conditionalSpecSubscriber) OnNextIf(t cesium.T) bool {
+	if t == nil {
+		s.violate(cesium.NilItemError)
+		return false
+	}
+
+	s.mux.Lock()
+	if s.terminated {
+		s.mux.Unlock()
+		onNextDropped(t)
+		return false
+	}
+	s.emitting = true
+	s.mux.Unlock()
+
+	accepted := s.conditional.OnNextIf(t)
+
+	s.mux.Lock()
+	s.emitting = false
+	err := s.pending
+	s.pending = nil
+	s.mux.Unlock()
+
+	if err != nil {
+		s.conditional.OnError(err)
+	}
+
+	return accepted
+}
+
+// specSubscription rejects the non-positive requests and makes the
+// cancellation idempotent.
+type specSubscription struct {
+	subscription cesium.Subscription
+	subscriber   *specSubscriber
+	cancelled    int32
+}
+
+func (s *specSubscription) Request(n int64) {
+	if n <= 0 {
+		s.Cancel()
+		s.subscriber.violate(cesium.NonPositiveRequestError)
+		return
+	}
+
+	if atomic.LoadInt32(&s.cancelled) == 0 {
+		s.subscription.Request(n)
+	}
+}
+
+func (s *specSubscription) RequestUnbounded() {
+	s.Request(math.MaxInt64)
+}
+
+func (s *specSubscription) Cancel() {
+	if atomic.CompareAndSwapInt32(&s.cancelled, 0, 1) {
+		s.subscription.Cancel()
+	}
+}
+
+// subscribeSpec subscribes the subscriber through a specSubscriber and returns
+// the subscription guarded by it.
+func subscribeSpec(onSubscribe func(cesium.Subscriber, cesium.Scheduler) cesium.Subscription, subscriber cesium.Subscriber) cesium.Subscription {
+	s := &specSubscriber{Subscriber: subscriber}
+
+	var spec cesium.Subscriber = s
+	if c, ok := subscriber.(ConditionalSubscriber); ok {
+		spec = &conditionalSpecSubscriber{s, c}
+	}
+
+	subscription := onSubscribe(spec, nil)
+	if subscription == nil {
+		return nil
+	}
+
+	return s.wrap(subscription)
+}
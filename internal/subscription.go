@@ -3,6 +3,7 @@ package internal
 import (
 	"math"
 	"sync"
+	"sync/atomic"
 
 	"github.com/DusanKasan/cesium"
 )
@@ -10,18 +11,29 @@ import (
 type Subscription struct {
 	CancelFunc  func()
 	RequestFunc func(int64)
+
+	cancelled int32
 }
 
+// Cancel cancels the subscription. Only the first call has an effect.
 func (s *Subscription) Cancel() {
-	s.CancelFunc()
+	if atomic.CompareAndSwapInt32(&s.cancelled, 0, 1) {
+		s.CancelFunc()
+	}
 }
 
+// Request requests n more items. Non-positive amounts are ignored here, they
+// are reported to the subscriber by the specSubscription it was handed.
 func (s *Subscription) Request(n int64) {
+	if n <= 0 {
+		return
+	}
+
 	s.RequestFunc(n)
 }
 
 func (s *Subscription) RequestUnbounded() {
-	s.RequestFunc(math.MaxInt64)
+	s.Request(math.MaxInt64)
 }
 
 type BufferedProxySubscription struct {
@@ -37,9 +49,7 @@ func (s *BufferedProxySubscription) SetSubscription(subscription cesium.Subscrip
 	s.subscription = subscription
 	if s.cancelled {
 		s.subscription.Cancel()
-	}
-
-	if s.requested > 0 {
+	} else if s.requested > 0 {
 		s.subscription.Request(s.requested)
 	}
 	s.mux.Unlock()
@@ -47,20 +57,33 @@ func (s *BufferedProxySubscription) SetSubscription(subscription cesium.Subscrip
 
 func (s *BufferedProxySubscription) Cancel() {
 	s.mux.Lock()
+	if s.cancelled {
+		s.mux.Unlock()
+		return
+	}
+	s.cancelled = true
+
 	if s.subscription != nil {
 		s.subscription.Cancel()
-	} else {
-		s.cancelled = true
 	}
 	s.mux.Unlock()
 }
 
 func (s *BufferedProxySubscription) Request(n int64) {
+	if n <= 0 {
+		return
+	}
+
 	s.mux.Lock()
+	if s.cancelled {
+		s.mux.Unlock()
+		return
+	}
+
 	if s.subscription != nil {
 		s.subscription.Request(n)
 	} else {
-		s.requested = s.requested + n
+		s.requested = addDemand(s.requested, n)
 	}
 	s.mux.Unlock()
 }
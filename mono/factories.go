@@ -19,12 +19,27 @@ func JustOrEmpty(t cesium.T) cesium.Mono {
 	return internal.MonoJustOrEmpty(t)
 }
 
+// From creates new cesium.Mono emitting the first item of the supplied
+// Publisher, which can come from another library, and cancelling it
+// afterwards. Such Publisher is guarded like in flux.From.
+func From(publisher cesium.Publisher) cesium.Mono {
+	return internal.MonoFrom(publisher)
+}
+
 // FromCallable creates new cesium.Mono that emits the item returned from the supplied function. If the function
-// returns nil, the returned Mono completes empty.
+// returns nil, the returned Mono completes empty. The function is called once, when the Mono is created, use
+// FromSupplier to call it for each subscription.
 func FromCallable(f func() cesium.T) cesium.Mono {
 	return internal.MonoFromCallable(f)
 }
 
+// FromSupplier creates new cesium.Mono that emits the item returned from the
+// supplied function, or the error if it returns one. The function is called
+// for each subscription. If it returns nil, the returned Mono completes empty.
+func FromSupplier(f func() (cesium.T, error)) cesium.Mono {
+	return internal.MonoFromSupplier(f)
+}
+
 // Empty creates new cesium.Mono that emits no items and completes normally.
 func Empty() cesium.Mono {
 	return internal.MonoEmpty()
@@ -62,7 +77,16 @@ func Create(f func(cesium.MonoSink)) cesium.Mono {
 
 // FromChannel creates a Mono from a channel that emits the first item from a
 // channel and then closes. If the channel is closed before emitting any items
-// the returned Mono just completes.
+// the returned Mono just completes. It can be subscribed to by one subscriber
+// at a time, the others receive cesium.SingleSubscriberError. Each subscriber
+// receives the next item from the channel.
 func FromChannel(c <-chan cesium.T) cesium.Mono {
 	return internal.MonoFromChannel(c)
 }
+
+// WhenDelayError creates a Mono that completes empty once all the supplied
+// monos terminate. If any of them fails, the rest are still awaited and the
+// errors are emitted as a cesium.CompositeError at the end.
+func WhenDelayError(monos ...cesium.Mono) cesium.Mono {
+	return internal.MonoWhenDelayError(monos...)
+}
@@ -35,3 +35,23 @@ func TestFromCallableCancellation(t *testing.T) {
 		ExpectNextCount(0).
 		Verify(t)
 }
+
+func TestFromCallableCalledOnce(t *testing.T) {
+	calls := 0
+	publisher := mono.FromCallable(func() cesium.T {
+		calls++
+		return calls
+	})
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(publisher).
+			ExpectNext(1).
+			ExpectComplete().
+			Verify(t)
+	}
+
+	if calls != 1 {
+		t.Errorf("The function was called %v times, expected once", calls)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCache(t *testing.T) {
+	mux := sync.Mutex{}
+	calls := 0
+
+	publisher := mono.
+		FromSupplier(func() (cesium.T, error) {
+			mux.Lock()
+			calls++
+			c := calls
+			mux.Unlock()
+			return c, nil
+		}).
+		Cache()
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestCacheWithTTL(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	mux := sync.Mutex{}
+	calls := 0
+
+	publisher := mono.
+		FromSupplier(func() (cesium.T, error) {
+			mux.Lock()
+			calls++
+			c := calls
+			mux.Unlock()
+			return c, nil
+		}).
+		CacheWithTTL(time.Second, scheduler)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+
+	scheduler.AdvanceTimeBy(time.Millisecond * 500)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+
+	scheduler.AdvanceTimeBy(time.Second)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(2).
+		ExpectComplete().
+		Verify(t)
+}
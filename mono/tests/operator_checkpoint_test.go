@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestCheckpoint(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := mono.
+		Error(err).
+		Checkpoint("source")
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(func(e error) bool {
+			var assemblyErr *cesium.AssemblyError
+			return errors.As(e, &assemblyErr) &&
+				errors.Unwrap(e) == err &&
+				len(assemblyErr.Trace) == 1 &&
+				assemblyErr.Trace[0] == `checkpoint("source")`
+		}).
+		Verify(t)
+}
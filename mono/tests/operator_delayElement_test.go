@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestDelayElement(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	publisher := mono.Just(1).DelayElement(time.Second, scheduler)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ThenAwait(time.Millisecond * 10).
+		ExpectNextCount(0).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelayElementEmpty(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	publisher := mono.Empty().DelayElement(time.Second, scheduler)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelayElementSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements > 1 {
+			return nil
+		}
+
+		source := mono.Empty()
+		if elements == 1 {
+			source = mono.Just(1)
+		}
+
+		return source.DelayElement(time.Millisecond)
+	})
+}
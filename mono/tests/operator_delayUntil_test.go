@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"errors"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestDelayUntil(t *testing.T) {
+	c := make(chan cesium.T)
+
+	publisher := mono.Just(1).DelayUntil(func(cesium.T) cesium.Publisher {
+		return flux.FromChannel(c)
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ThenAwait(time.Millisecond * 10).
+		ExpectNextCount(0).
+		Then(func() {
+			c <- 2
+			close(c)
+		}).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDelayUntilOnError(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := mono.Just(1).DelayUntil(func(cesium.T) cesium.Publisher {
+		return mono.Error(err)
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestDelayUntilSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements > 1 {
+			return nil
+		}
+
+		source := mono.Empty()
+		if elements == 1 {
+			source = mono.Just(1)
+		}
+
+		return source.DelayUntil(func(cesium.T) cesium.Publisher {
+			return mono.Just(true)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestElapsed(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	publisher := mono.Just(1).
+		DelayElement(time.Second*2, scheduler).
+		Elapsed(scheduler)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ThenAwait(time.Millisecond * 10).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second * 2)
+		}).
+		ExpectNextMatches(func(t cesium.T) bool {
+			e := t.(cesium.ElapsedItem)
+			return e.Item == 1 && e.Elapsed == time.Second*2
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestElapsedScalarMono(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	publisher := mono.Just(1).
+		Elapsed(scheduler).
+		Map(func(t cesium.T) cesium.T {
+			return t
+		})
+
+	// The item is emitted upon request, a second after the subscription.
+	verifier.
+		Create(publisher).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNextMatches(func(t cesium.T) bool {
+			e := t.(cesium.ElapsedItem)
+			return e.Item == 1 && e.Elapsed == time.Second
+		}).
+		ExpectComplete().
+		Verify(t)
+}
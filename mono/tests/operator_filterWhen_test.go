@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestFilterWhen(t *testing.T) {
+	publisher := mono.
+		Just(5).
+		FilterWhen(func(a cesium.T) cesium.Publisher {
+			return mono.Just(a.(int) > 4)
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(5).
+		ExpectComplete().
+		Verify(t)
+
+	publisher2 := mono.
+		Just(3).
+		FilterWhen(func(a cesium.T) cesium.Publisher {
+			return mono.Just(a.(int) > 4)
+		})
+
+	verifier.
+		Create(publisher2).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFilterWhenSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements > 1 {
+			return nil
+		}
+
+		return mono.
+			Just(elements).
+			FilterWhen(func(cesium.T) cesium.Publisher {
+				return mono.Just(elements == 1)
+			})
+	})
+}
@@ -39,3 +39,26 @@ func TestFromChannelDropsItemsAfterFirst(t *testing.T) {
 		ExpectComplete().
 		Verify(t)
 }
+
+func TestFromChannelResubscribe(t *testing.T) {
+	c := make(chan cesium.T, 2)
+	c <- 1
+	c <- 2
+	close(c)
+
+	m := mono.FromChannel(c)
+
+	for _, item := range []cesium.T{1, 2} {
+		verifier.
+			Create(m).
+			ExpectNext(item).
+			ExpectComplete().
+			Verify(t)
+	}
+
+	verifier.
+		Create(m).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
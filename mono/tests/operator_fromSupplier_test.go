@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestFromSupplier(t *testing.T) {
+	calls := 0
+	publisher := mono.FromSupplier(func() (cesium.T, error) {
+		calls++
+		return calls, nil
+	})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+
+	verifier.
+		Create(publisher).
+		ExpectNext(2).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromSupplierError(t *testing.T) {
+	err := errors.New("err")
+	publisher := mono.FromSupplier(func() (cesium.T, error) {
+		return nil, err
+	})
+
+	verifier.
+		Create(publisher).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestFromSupplierNil(t *testing.T) {
+	publisher := mono.FromSupplier(func() (cesium.T, error) {
+		return nil, nil
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromSupplierCallsSupplierOnRequest(t *testing.T) {
+	calls := int32(0)
+	publisher := mono.FromSupplier(func() (cesium.T, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	})
+
+	verifier.
+		Create(publisher).
+		ThenAwait(time.Millisecond * 20).
+		Then(func() {
+			if c := atomic.LoadInt32(&calls); c != 0 {
+				t.Errorf("Expected the supplier not to be called before the request, got %v calls", c)
+			}
+		}).
+		ThenRequest(1).
+		ExpectNext(int32(1)).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromSupplierSpec(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		if elements > 1 {
+			return nil
+		}
+
+		return mono.FromSupplier(func() (cesium.T, error) {
+			if elements == 0 {
+				return nil, nil
+			}
+
+			return 1, nil
+		})
+	})
+}
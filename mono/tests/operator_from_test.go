@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+// itemsPublisher is a Publisher implemented without the library, emitting
+// the items synchronously from Request.
+type itemsPublisher []cesium.T
+
+func (p itemsPublisher) Subscribe(s cesium.Subscriber) cesium.Subscription {
+	sub := &itemsSubscription{items: p, subscriber: s}
+	s.OnSubscribe(sub)
+	return sub
+}
+
+type itemsSubscription struct {
+	items      []cesium.T
+	subscriber cesium.Subscriber
+	cancelled  bool
+}
+
+func (s *itemsSubscription) Request(n int64) {
+	for ; n > 0 && !s.cancelled && len(s.items) > 0; n-- {
+		item := s.items[0]
+		s.items = s.items[1:]
+		s.subscriber.OnNext(item)
+	}
+
+	if !s.cancelled && len(s.items) == 0 {
+		s.cancelled = true
+		s.subscriber.OnComplete()
+	}
+}
+
+func (s *itemsSubscription) RequestUnbounded() {
+	s.Request(int64(len(s.items)))
+}
+
+func (s *itemsSubscription) Cancel() {
+	s.cancelled = true
+}
+
+func TestFrom(t *testing.T) {
+	m := mono.
+		From(itemsPublisher{1, 2, 3}).
+		Map(func(t cesium.T) cesium.T {
+			return t.(int) + 1
+		})
+
+	verifier.
+		Create(m).
+		ExpectNext(2).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestFromEmpty(t *testing.T) {
+	m := mono.From(itemsPublisher{})
+
+	verifier.
+		Create(m).
+		ThenRequest(1).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDeferForeignMono(t *testing.T) {
+	m := mono.Defer(func() cesium.Mono {
+		return mono.From(itemsPublisher{1})
+	})
+
+	verifier.
+		Create(m).
+		ExpectNext(1).
+		ExpectComplete().
+		Verify(t)
+}
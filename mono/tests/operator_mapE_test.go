@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestMapE(t *testing.T) {
+	publisher := mono.
+		Just(1).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return 10 * a.(int), nil
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectNext(10).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestMapEError(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := mono.
+		Just(1).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return nil, err
+		})
+
+	verifier.
+		Create(publisher).
+		ExpectError(err).
+		Verify(t)
+}
+
+func TestMapEErrorNonScalar(t *testing.T) {
+	err := errors.New("err")
+
+	publisher := mono.
+		Just(1).
+		DoOnNext(func(cesium.T) {}).
+		MapE(func(a cesium.T) (cesium.T, error) {
+			return nil, err
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectError(err).
+		Verify(t)
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestShare(t *testing.T) {
+	mux := sync.Mutex{}
+	calls := 0
+	c := make(chan cesium.T)
+
+	publisher := mono.
+		FromChannel(c).
+		DoOnSubscribe(func(cesium.Subscription) {
+			mux.Lock()
+			calls++
+			mux.Unlock()
+		}).
+		Share()
+
+	// The item is sent once both subscribers are subscribed, so that neither
+	// of them arrives after the shared source completed.
+	subscribed := sync.WaitGroup{}
+	subscribed.Add(2)
+	go func() {
+		subscribed.Wait()
+		c <- 1
+	}()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verifier.
+				Create(publisher.DoOnSubscribe(func(cesium.Subscription) {
+					subscribed.Done()
+				})).
+				ExpectNext(1).
+				ExpectComplete().
+				Verify(t)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("The shared item was not received by both subscribers within 5s")
+	}
+
+	mux.Lock()
+	if calls != 1 {
+		t.Errorf("Expected one subscription to the source, got %v", calls)
+	}
+	mux.Unlock()
+}
+
+func TestShareCancelsSourceWhenLastSubscriberLeaves(t *testing.T) {
+	cancelled := make(chan bool, 1)
+
+	publisher := mono.
+		Never().
+		DoOnCancel(func() {
+			cancelled <- true
+		}).
+		Share()
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ThenCancel().
+		Then(func() {
+			select {
+			case <-cancelled:
+			case <-time.After(time.Millisecond * 100):
+				t.Errorf("Source not cancelled after the last subscriber left")
+			}
+		}).
+		Verify(t)
+}
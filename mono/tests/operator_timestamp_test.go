@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"testing"
+
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestTimestamp(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	start := scheduler.Now()
+
+	publisher := mono.Just(1).
+		DelayElement(time.Second, scheduler).
+		Timestamp(scheduler)
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ThenAwait(time.Millisecond * 10).
+		Then(func() {
+			scheduler.AdvanceTimeBy(time.Second)
+		}).
+		ExpectNextMatches(func(t cesium.T) bool {
+			ts := t.(cesium.TimestampedItem)
+			return ts.Item == 1 && ts.Timestamp.Equal(start.Add(time.Second))
+		}).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestTimestampScalarMono(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+
+	publisher := mono.Just(1).
+		Timestamp(scheduler).
+		Map(func(t cesium.T) cesium.T {
+			return t
+		})
+
+	// The timestamp must be taken upon emission, not upon assembly, even if
+	// followed by operators fused at assembly.
+	scheduler.AdvanceTimeBy(time.Hour)
+
+	verifier.
+		Create(publisher).
+		ExpectNextMatches(func(t cesium.T) bool {
+			ts := t.(cesium.TimestampedItem)
+			return ts.Item == 1 && ts.Timestamp.Equal(scheduler.Now())
+		}).
+		ExpectComplete().
+		Verify(t)
+}
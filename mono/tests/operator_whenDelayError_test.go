@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestWhenDelayError(t *testing.T) {
+	publisher := mono.WhenDelayError(
+		mono.Just(1),
+		mono.Just(2),
+	)
+
+	verifier.
+		Create(publisher).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestWhenDelayErrorOnError(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	completed := false
+
+	publisher := mono.WhenDelayError(
+		mono.Error(err1),
+		mono.Just(1).DoOnNext(func(t cesium.T) {
+			completed = true
+		}),
+		mono.Error(err2),
+	)
+
+	verifier.
+		Create(publisher).
+		ExpectErrorMatches(func(err error) bool {
+			return completed && errors.Is(err, err1) && errors.Is(err, err2)
+		}).
+		Verify(t)
+}
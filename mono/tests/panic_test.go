@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func isPanicWith(value interface{}) func(error) bool {
+	return func(err error) bool {
+		var panicErr *cesium.PanicError
+		return errors.As(err, &panicErr) && panicErr.Value == value && len(panicErr.Stack) > 0
+	}
+}
+
+func TestMapPanic(t *testing.T) {
+	publisher := mono.
+		Just(1).
+		Map(func(a cesium.T) cesium.T {
+			panic("boom")
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestFromCallablePanic(t *testing.T) {
+	publisher := mono.FromCallable(func() cesium.T {
+		panic("boom")
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestCreatePanic(t *testing.T) {
+	publisher := mono.Create(func(sink cesium.MonoSink) {
+		panic("boom")
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestDeferPanic(t *testing.T) {
+	publisher := mono.Defer(func() cesium.Mono {
+		panic("boom")
+	})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
+
+func TestDoOnNextPanic(t *testing.T) {
+	publisher := mono.
+		FromCallable(func() cesium.T {
+			return 1
+		}).
+		DoOnNext(func(a cesium.T) {
+			panic("boom")
+		})
+
+	verifier.
+		Create(publisher).
+		ThenRequest(1).
+		ExpectErrorMatches(isPanicWith("boom")).
+		Verify(t)
+}
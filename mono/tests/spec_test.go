@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestRequestNonPositive(t *testing.T) {
+	cancelled := int32(0)
+
+	m := mono.
+		Just(1).
+		DoOnCancel(func() {
+			atomic.AddInt32(&cancelled, 1)
+		})
+
+	verifier.
+		Create(m).
+		ThenRequest(-1).
+		ExpectError(cesium.NonPositiveRequestError).
+		Verify(t)
+
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Errorf("Expected the source to be cancelled once, was cancelled %v times", cancelled)
+	}
+}
+
+func TestNilItem(t *testing.T) {
+	m := mono.
+		Just(1).
+		Map(func(cesium.T) cesium.T {
+			return nil
+		})
+
+	verifier.
+		Create(m).
+		ThenRequest(1).
+		ExpectError(cesium.NilItemError).
+		Verify(t)
+}
@@ -0,0 +1,59 @@
+// Package processors provides implementations of cesium.FluxProcessor. A
+// processor can be subscribed to a Publisher, or have its OnNext, OnError and
+// OnComplete methods called directly, and it relays the received signals to
+// its own subscribers.
+//
+// The signals are always delivered to the subscribers serially, as required by
+// the Reactive Streams specification, even if the OnNext method of the
+// processor is called from multiple goroutines at once.
+package processors
+
+import (
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/internal"
+)
+
+// UnicastProcessor creates a processor that allows only a single subscriber.
+// Items are buffered until the subscriber requests them, the buffer holds up
+// to bufferSize items (256 if bufferSize is not positive). The processor only
+// requests as many items from its upstream as fit in the buffer. Any
+// additional subscriber receives cesium.SingleSubscriberError.
+func UnicastProcessor(bufferSize int) cesium.FluxProcessor {
+	return internal.UnicastFluxProcessor(bufferSize)
+}
+
+// DirectProcessor creates a processor that emits the items to all of its
+// subscribers as they come, without any buffering. A subscriber that has not
+// requested the item is terminated with cesium.DownstreamUnableToKeepUpError.
+// Subscribers that subscribe after the processor terminated only receive the
+// terminal signal.
+func DirectProcessor() cesium.FluxProcessor {
+	return internal.DirectFluxProcessor()
+}
+
+// EmitterProcessor creates a processor that emits the items to all of its
+// subscribers, at the pace of the slowest one. Items are buffered until all
+// the subscribers request them, the buffer holds up to bufferSize items (256
+// if bufferSize is not positive). The processor only requests as many items
+// from its upstream as fit in the buffer. If there is no subscriber, the items
+// are kept in the buffer for the first one.
+func EmitterProcessor(bufferSize int) cesium.FluxProcessor {
+	return internal.EmitterFluxProcessor(bufferSize)
+}
+
+// ReplayProcessor creates a processor that records the items and replays them
+// to every subscriber, each at its own pace, followed by the terminal signal.
+// Only the last history items that are not older than ttl are replayed to a
+// new subscriber. Negative history means there is no size limit and ttl that
+// is not positive means there is no time limit. The optional scheduler is used
+// as the clock to tell the age of the items.
+func ReplayProcessor(history int, ttl time.Duration, scheduler ...cesium.TimedScheduler) cesium.FluxProcessor {
+	var clock cesium.Clock = internal.TimerScheduler()
+	if len(scheduler) > 0 {
+		clock = scheduler[0]
+	}
+
+	return internal.ReplayFluxProcessor(history, ttl, clock)
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/processors"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestDirectProcessor(t *testing.T) {
+	p := processors.DirectProcessor()
+
+	verifier.
+		Create(p).
+		ThenRequest(2).
+		Then(func() {
+			p.OnNext(1)
+			p.OnNext(2)
+			p.OnComplete()
+		}).
+		ExpectNext(1, 2).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestDirectProcessorWithoutDemand(t *testing.T) {
+	p := processors.DirectProcessor()
+
+	verifier.
+		Create(p).
+		ThenRequest(1).
+		Then(func() {
+			p.OnNext(1)
+			p.OnNext(2)
+		}).
+		ExpectNext(1).
+		ExpectError(cesium.DownstreamUnableToKeepUpError).
+		Verify(t)
+}
+
+func TestDirectProcessorDoesNotBuffer(t *testing.T) {
+	p := processors.DirectProcessor()
+	p.OnNext(1)
+	p.OnComplete()
+
+	verifier.
+		Create(p).
+		ExpectComplete().
+		Verify(t)
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/processors"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+// serialSubscriber counts the received items and records whether OnNext was
+// ever called concurrently.
+type serialSubscriber struct {
+	inFlight   int32
+	concurrent int32
+	count      int32
+	done       chan bool
+}
+
+func (s *serialSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	if subscription != nil {
+		subscription.Request(math.MaxInt64)
+	}
+}
+
+func (s *serialSubscriber) OnNext(cesium.T) {
+	if atomic.AddInt32(&s.inFlight, 1) > 1 {
+		atomic.StoreInt32(&s.concurrent, 1)
+	}
+	time.Sleep(time.Microsecond)
+	atomic.AddInt32(&s.count, 1)
+	atomic.AddInt32(&s.inFlight, -1)
+}
+
+func (s *serialSubscriber) OnError(error) {
+	if s.done != nil {
+		close(s.done)
+	}
+}
+
+func (s *serialSubscriber) OnComplete() {
+	if s.done != nil {
+		close(s.done)
+	}
+}
+
+func TestEmitterProcessor(t *testing.T) {
+	p := processors.EmitterProcessor(4)
+
+	// Buffered until the first subscriber comes.
+	p.OnNext(1)
+	p.OnNext(2)
+
+	verifier.
+		Create(p).
+		ExpectNext(1, 2).
+		Then(func() {
+			p.OnNext(3)
+			p.OnComplete()
+		}).
+		ExpectNext(3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestEmitterProcessorPacesToSlowestSubscriber(t *testing.T) {
+	p := processors.EmitterProcessor(4)
+	fastChecked := make(chan bool)
+
+	go func() {
+		// Let both of the subscribers subscribe first.
+		time.Sleep(time.Millisecond * 20)
+		p.OnNext(1)
+		p.OnNext(2)
+		p.OnComplete()
+	}()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer close(fastChecked)
+		verifier.
+			Create(p).
+			ThenRequest(2).
+			ThenAwait(time.Millisecond * 50).
+			ExpectNextCount(0).
+			Then(func() {
+				fastChecked <- true
+			}).
+			ExpectNextCount(2).
+			ExpectComplete().
+			Verify(t)
+	}()
+
+	go func() {
+		defer wg.Done()
+		verifier.
+			Create(p).
+			Then(func() {
+				<-fastChecked
+			}).
+			ThenRequest(2).
+			ExpectNextCount(2).
+			ExpectComplete().
+			Verify(t)
+	}()
+	wg.Wait()
+}
+
+func TestEmitterProcessorConcurrentOnNext(t *testing.T) {
+	p := processors.EmitterProcessor(1000)
+	s := &serialSubscriber{done: make(chan bool)}
+	p.Subscribe(s)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p.OnNext(j)
+			}
+		}()
+	}
+	wg.Wait()
+	p.OnComplete()
+
+	select {
+	case <-s.done:
+	case <-time.After(time.Second):
+		t.Fatalf("Processor did not complete")
+	}
+
+	if atomic.LoadInt32(&s.concurrent) != 0 {
+		t.Errorf("OnNext was called concurrently")
+	}
+
+	if c := atomic.LoadInt32(&s.count); c != 1000 {
+		t.Errorf("Expected 1000 items, got %v", c)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium/processors"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestReplayProcessor(t *testing.T) {
+	p := processors.ReplayProcessor(2, 0)
+	p.OnNext(1)
+	p.OnNext(2)
+	p.OnNext(3)
+	p.OnComplete()
+
+	verifier.
+		Create(p).
+		ExpectNext(2, 3).
+		ExpectComplete().
+		Verify(t)
+
+	verifier.
+		Create(p).
+		ExpectNext(2, 3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestReplayProcessorTimeLimit(t *testing.T) {
+	scheduler := verifier.NewVirtualTimeScheduler()
+	p := processors.ReplayProcessor(-1, time.Second, scheduler)
+
+	p.OnNext(1)
+	scheduler.AdvanceTimeBy(time.Second * 2)
+	p.OnNext(2)
+
+	verifier.
+		Create(p).
+		ExpectNext(2).
+		Then(func() {
+			p.OnNext(3)
+			p.OnComplete()
+		}).
+		ExpectNext(3).
+		ExpectComplete().
+		Verify(t)
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/processors"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestUnicastProcessor(t *testing.T) {
+	p := processors.UnicastProcessor(4)
+	flux.Just(1, 2, 3).Subscribe(p)
+
+	verifier.
+		Create(p).
+		ExpectNext(1, 2, 3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestUnicastProcessorSecondSubscriber(t *testing.T) {
+	p := processors.UnicastProcessor(4)
+	p.Subscribe(&serialSubscriber{})
+
+	verifier.
+		Create(p).
+		ExpectError(cesium.SingleSubscriberError).
+		Verify(t)
+}
+
+func TestUnicastProcessorOverflow(t *testing.T) {
+	p := processors.UnicastProcessor(2)
+	p.OnNext(1)
+	p.OnNext(2)
+	p.OnNext(3)
+
+	verifier.
+		Create(p).
+		ExpectNext(1, 2).
+		ExpectError(cesium.DownstreamUnableToKeepUpError).
+		Verify(t)
+}
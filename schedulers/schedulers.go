@@ -0,0 +1,24 @@
+// Package schedulers provides implementations of cesium.Scheduler to be used
+// with the operators that allow choosing where the work is executed, like
+// ParallelFlux.RunOn.
+package schedulers
+
+import (
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/internal"
+)
+
+// Parallel returns the shared scheduler backed by a pool of runtime.NumCPU()
+// goroutines, suited for CPU-bound work.
+func Parallel() cesium.Scheduler {
+	return internal.ParallelScheduler()
+}
+
+// NewWorkerPool creates a scheduler backed by its own pool of the specified
+// number of goroutines (runtime.NumCPU() if workers is not positive). The
+// scheduled actions are queued until one of the goroutines is free. The
+// caller must dispose the scheduler once it is no longer used, otherwise the
+// goroutines are never released.
+func NewWorkerPool(workers int) cesium.DisposableScheduler {
+	return internal.WorkerPoolScheduler(workers)
+}
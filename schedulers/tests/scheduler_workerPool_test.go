@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/schedulers"
+)
+
+func TestWorkerPool(t *testing.T) {
+	pool := schedulers.NewWorkerPool(2)
+	defer pool.Dispose()
+
+	wg := sync.WaitGroup{}
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		pool.Schedule(func(cesium.Canceller) {
+			wg.Done()
+		})
+	}
+
+	wg.Wait()
+}
+
+func TestWorkerPoolDisposeReleasesGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	pool := schedulers.NewWorkerPool(8)
+
+	done := make(chan struct{})
+	pool.Schedule(func(cesium.Canceller) {
+		close(done)
+	})
+	<-done
+
+	pool.Dispose()
+	if !pool.IsDisposed() {
+		t.Errorf("The worker pool is not disposed")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("The goroutines were not released. Before: %v, After: %v", before, runtime.NumGoroutine())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerPoolScheduleAfterDispose(t *testing.T) {
+	pool := schedulers.NewWorkerPool(1)
+	pool.Dispose()
+
+	executed := make(chan struct{})
+	pool.Schedule(func(cesium.Canceller) {
+		close(executed)
+	})
+
+	select {
+	case <-executed:
+		t.Errorf("The action was executed after the worker pool was disposed")
+	case <-time.After(time.Millisecond * 10):
+	}
+}
@@ -0,0 +1,69 @@
+// Package sinks provides the sinks that allow emitting signals
+// programmatically to any number of subscribers, viewed as a Flux or a Mono.
+//
+// Unlike the cesium.FluxSink used by flux.Create, these sinks are not bound to
+// a single subscription and can be used from multiple goroutines. Instead of
+// panicking or silently dropping the signals, every emission attempt reports
+// its outcome as a cesium.EmitResult. A concurrent emission is rejected with
+// FailNonSerialized, so it is up to the caller to retry or drop it.
+package sinks
+
+import (
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/internal"
+)
+
+// OK means the signal was accepted.
+const OK = cesium.EmitResultOK
+
+// FailTerminated means the sink has already terminated.
+const FailTerminated = cesium.EmitResultFailTerminated
+
+// FailOverflow means the buffer of the sink is full because its subscribers
+// did not request the items fast enough.
+const FailOverflow = cesium.EmitResultFailOverflow
+
+// FailCancelled means the subscriber of the sink has cancelled.
+const FailCancelled = cesium.EmitResultFailCancelled
+
+// FailNonSerialized means another signal was being emitted to the sink
+// concurrently.
+const FailNonSerialized = cesium.EmitResultFailNonSerialized
+
+// One creates a sink that emits at most one item, or an error, and replays it
+// to every subscriber of its Mono view.
+func One() cesium.OneSink {
+	return internal.OneSink()
+}
+
+// Empty creates a sink that only completes or fails, and replays the terminal
+// signal to every subscriber of its Mono view.
+func Empty() cesium.EmptySink {
+	return internal.EmptySink()
+}
+
+// ManyUnicast creates a sink that allows only a single subscriber. Items are
+// buffered until the subscriber requests them. Once the buffer of 256 items is
+// full, the emissions fail with FailOverflow, and once the subscriber cancels,
+// they fail with FailCancelled. Any additional subscriber receives
+// cesium.SingleSubscriberError.
+func ManyUnicast() cesium.ManySink {
+	return internal.ManyUnicastSink()
+}
+
+// ManyMulticast creates a sink that emits the items to all of its subscribers,
+// at the pace of the slowest one. Items are buffered until all the subscribers
+// request them. Once the buffer of 256 items is full, the emissions fail with
+// FailOverflow. If there is no subscriber, the items are kept in the buffer for
+// the first one.
+func ManyMulticast() cesium.ManySink {
+	return internal.ManyMulticastSink()
+}
+
+// ManyReplay creates a sink that records the items and replays them to every
+// subscriber, each at its own pace, followed by the terminal signal. Only the
+// last history items are replayed to a new subscriber, negative history means
+// there is no limit.
+func ManyReplay(history int) cesium.ManySink {
+	return internal.ManyReplaySink(history)
+}
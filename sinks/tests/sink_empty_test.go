@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium/sinks"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestEmptySink(t *testing.T) {
+	sink := sinks.Empty()
+
+	verifier.
+		Create(sink.AsMono()).
+		Then(func() {
+			expectResult(t, sinks.OK, sink.TryEmitComplete())
+			expectResult(t, sinks.FailTerminated, sink.TryEmitError(testError))
+		}).
+		ExpectComplete().
+		Verify(t)
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/sinks"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestManyMulticastSink(t *testing.T) {
+	sink := sinks.ManyMulticast()
+
+	// Buffered until the first subscriber comes.
+	expectResult(t, sinks.OK, sink.TryEmitNext(1))
+
+	verifier.
+		Create(sink.AsFlux()).
+		ExpectNext(1).
+		Then(func() {
+			expectResult(t, sinks.OK, sink.TryEmitNext(2))
+			expectResult(t, sinks.OK, sink.TryEmitError(testError))
+			expectResult(t, sinks.FailTerminated, sink.TryEmitComplete())
+		}).
+		ExpectNext(2).
+		ExpectError(testError).
+		Verify(t)
+}
+
+// reentrantSubscriber tries to emit to the sink while it is handling an
+// emission from the same sink.
+type reentrantSubscriber struct {
+	sink   cesium.ManySink
+	result cesium.EmitResult
+}
+
+func (s *reentrantSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	if subscription != nil {
+		subscription.RequestUnbounded()
+	}
+}
+
+func (s *reentrantSubscriber) OnNext(cesium.T) {
+	s.result = s.sink.TryEmitNext(2)
+}
+
+func (s *reentrantSubscriber) OnComplete()   {}
+func (s *reentrantSubscriber) OnError(error) {}
+
+func TestManyMulticastSinkNonSerialized(t *testing.T) {
+	sink := sinks.ManyMulticast()
+	subscriber := &reentrantSubscriber{sink: sink}
+	sink.AsFlux().Subscribe(subscriber)
+
+	expectResult(t, sinks.OK, sink.TryEmitNext(1))
+	expectResult(t, sinks.FailNonSerialized, subscriber.result)
+}
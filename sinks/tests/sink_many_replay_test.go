@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium/sinks"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestManyReplaySink(t *testing.T) {
+	sink := sinks.ManyReplay(2)
+	expectResult(t, sinks.OK, sink.TryEmitNext(1))
+	expectResult(t, sinks.OK, sink.TryEmitNext(2))
+	expectResult(t, sinks.OK, sink.TryEmitNext(3))
+	expectResult(t, sinks.OK, sink.TryEmitComplete())
+	expectResult(t, sinks.FailTerminated, sink.TryEmitNext(4))
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(sink.AsFlux()).
+			ExpectNext(2, 3).
+			ExpectComplete().
+			Verify(t)
+	}
+}
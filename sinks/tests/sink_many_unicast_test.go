@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/sinks"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+const testError = testErr("test")
+
+type testErr string
+
+func (e testErr) Error() string {
+	return string(e)
+}
+
+type noopSubscriber struct{}
+
+func (*noopSubscriber) OnSubscribe(cesium.Subscription) {}
+func (*noopSubscriber) OnNext(cesium.T)                 {}
+func (*noopSubscriber) OnComplete()                     {}
+func (*noopSubscriber) OnError(error)                   {}
+
+func expectResult(t *testing.T, expected cesium.EmitResult, actual cesium.EmitResult) {
+	t.Helper()
+	if actual != expected {
+		t.Errorf("Expected emit result %v, got %v", expected, actual)
+	}
+}
+
+func TestManyUnicastSink(t *testing.T) {
+	sink := sinks.ManyUnicast()
+	expectResult(t, sinks.OK, sink.TryEmitNext(1))
+	expectResult(t, sinks.OK, sink.TryEmitNext(2))
+
+	verifier.
+		Create(sink.AsFlux()).
+		ExpectNext(1, 2).
+		Then(func() {
+			expectResult(t, sinks.OK, sink.TryEmitNext(3))
+			expectResult(t, sinks.OK, sink.TryEmitComplete())
+			expectResult(t, sinks.FailTerminated, sink.TryEmitNext(4))
+			expectResult(t, sinks.FailTerminated, sink.TryEmitError(testError))
+		}).
+		ExpectNext(3).
+		ExpectComplete().
+		Verify(t)
+}
+
+func TestManyUnicastSinkOverflow(t *testing.T) {
+	sink := sinks.ManyUnicast()
+	for i := 0; i < 256; i++ {
+		expectResult(t, sinks.OK, sink.TryEmitNext(i))
+	}
+	expectResult(t, sinks.FailOverflow, sink.TryEmitNext(256))
+
+	verifier.
+		Create(sink.AsFlux()).
+		ThenRequest(1).
+		ExpectNext(0).
+		Then(func() {
+			expectResult(t, sinks.OK, sink.TryEmitNext(256))
+		}).
+		ThenCancel().
+		Verify(t)
+}
+
+func TestManyUnicastSinkCancelled(t *testing.T) {
+	sink := sinks.ManyUnicast()
+
+	verifier.
+		Create(sink.AsFlux()).
+		Then(func() {
+			expectResult(t, sinks.OK, sink.TryEmitNext(1))
+		}).
+		ExpectNext(1).
+		ThenCancel().
+		Verify(t)
+
+	expectResult(t, sinks.FailCancelled, sink.TryEmitNext(2))
+}
+
+func TestManyUnicastSinkSecondSubscriber(t *testing.T) {
+	sink := sinks.ManyUnicast()
+	sink.AsFlux().Subscribe(&noopSubscriber{})
+
+	verifier.
+		Create(sink.AsFlux()).
+		ExpectError(cesium.SingleSubscriberError).
+		Verify(t)
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DusanKasan/cesium/sinks"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestOneSink(t *testing.T) {
+	sink := sinks.One()
+	expectResult(t, sinks.OK, sink.TryEmitNext(1))
+	expectResult(t, sinks.FailTerminated, sink.TryEmitNext(2))
+	expectResult(t, sinks.FailTerminated, sink.TryEmitComplete())
+
+	for i := 0; i < 2; i++ {
+		verifier.
+			Create(sink.AsMono()).
+			ExpectNext(1).
+			ExpectComplete().
+			Verify(t)
+	}
+}
+
+func TestOneSinkError(t *testing.T) {
+	sink := sinks.One()
+
+	verifier.
+		Create(sink.AsMono()).
+		Then(func() {
+			expectResult(t, sinks.OK, sink.TryEmitError(testError))
+		}).
+		ExpectError(testError).
+		Verify(t)
+}
@@ -0,0 +1,142 @@
+package verifier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+)
+
+type virtualCanceller struct {
+	mux       sync.Mutex
+	cancelled bool
+	onCancel  func()
+}
+
+func (c *virtualCanceller) IsCancelled() bool {
+	c.mux.Lock()
+	cancelled := c.cancelled
+	c.mux.Unlock()
+
+	return cancelled
+}
+
+func (c *virtualCanceller) OnCancel(f func()) {
+	c.mux.Lock()
+	c.onCancel = f
+	c.mux.Unlock()
+}
+
+func (c *virtualCanceller) Cancel() {
+	c.mux.Lock()
+	c.cancelled = true
+	onCancel := c.onCancel
+	c.mux.Unlock()
+
+	if onCancel != nil {
+		onCancel()
+	}
+}
+
+type virtualTask struct {
+	due       time.Time
+	sequence  int64
+	action    func(cesium.Canceller)
+	canceller *virtualCanceller
+}
+
+// VirtualTimeScheduler is a cesium.TimedScheduler whose clock only moves when
+// told to via AdvanceTimeBy. Pass it to the time based operators (like
+// Flux.DelayElements) to test them without waiting for the real time to pass.
+//
+// Actions scheduled via Schedule are executed immediately on a separate
+// goroutine, only the delayed actions are subject to the virtual time.
+type VirtualTimeScheduler struct {
+	mux      sync.Mutex
+	now      time.Time
+	sequence int64
+	tasks    []*virtualTask
+}
+
+// NewVirtualTimeScheduler creates a VirtualTimeScheduler with its clock set to
+// the current time.
+func NewVirtualTimeScheduler() *VirtualTimeScheduler {
+	return &VirtualTimeScheduler{now: time.Now()}
+}
+
+// Schedule executes the action on a separate goroutine.
+func (s *VirtualTimeScheduler) Schedule(action func(cesium.Canceller)) cesium.Cancellable {
+	c := &virtualCanceller{}
+
+	go action(c)
+
+	return &subscription{onCancel: c.Cancel}
+}
+
+// ScheduleAfter queues the action to be executed when the virtual clock
+// reaches the current virtual time plus the delay.
+func (s *VirtualTimeScheduler) ScheduleAfter(delay time.Duration, action func(cesium.Canceller)) cesium.Cancellable {
+	c := &virtualCanceller{}
+
+	s.mux.Lock()
+	s.sequence++
+	s.tasks = append(s.tasks, &virtualTask{
+		due:       s.now.Add(delay),
+		sequence:  s.sequence,
+		action:    action,
+		canceller: c,
+	})
+	s.mux.Unlock()
+
+	return &subscription{onCancel: c.Cancel}
+}
+
+// Now returns the current virtual time.
+func (s *VirtualTimeScheduler) Now() time.Time {
+	s.mux.Lock()
+	now := s.now
+	s.mux.Unlock()
+
+	return now
+}
+
+// AdvanceTimeBy moves the virtual clock forward by the duration, executing all
+// the actions that become due, in the order of their due time, synchronously.
+func (s *VirtualTimeScheduler) AdvanceTimeBy(duration time.Duration) {
+	s.mux.Lock()
+	target := s.now.Add(duration)
+	s.mux.Unlock()
+
+	for {
+		s.mux.Lock()
+		next := -1
+		for i, task := range s.tasks {
+			if task.due.After(target) {
+				continue
+			}
+
+			if next == -1 ||
+				task.due.Before(s.tasks[next].due) ||
+				(task.due.Equal(s.tasks[next].due) && task.sequence < s.tasks[next].sequence) {
+				next = i
+			}
+		}
+
+		if next == -1 {
+			s.now = target
+			s.mux.Unlock()
+			return
+		}
+
+		task := s.tasks[next]
+		s.tasks = append(s.tasks[:next], s.tasks[next+1:]...)
+		if task.due.After(s.now) {
+			s.now = task.due
+		}
+		s.mux.Unlock()
+
+		if !task.canceller.IsCancelled() {
+			task.action(task.canceller)
+		}
+	}
+}
@@ -0,0 +1,868 @@
+package verifier
+
+import (
+	"fmt"
+	"math"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DusanKasan/cesium"
+)
+
+// specTimeout is how long the rule checks wait for an expected signal.
+const specTimeout = time.Millisecond * 200
+
+// specSettle is how long the rule checks wait to make sure no unexpected
+// signal arrives.
+const specSettle = time.Millisecond * 20
+
+// rule is one of the Reactive Streams rules checked by VerifyPublisher or
+// VerifySubscriber.
+type rule struct {
+	id          string
+	description string
+	check       func(t testing.TB)
+}
+
+// verifyRules reports each of the rules as a separate subtest named after it.
+// If t can not run subtests, the rules are checked one after another, each on
+// its own goroutine, so that a FailNow ends only the check of that rule.
+func verifyRules(t testing.TB, rules []rule) {
+	for _, r := range rules {
+		r := r
+		check := func(t testing.TB) {
+			defer func() {
+				if p := recover(); p != nil {
+					t.Errorf("Rule %s: panic: %v\n%s", r.id, p, debug.Stack())
+				}
+			}()
+
+			r.check(t)
+		}
+
+		name := fmt.Sprintf("Rule %s: %s", r.id, r.description)
+		if runner, ok := t.(interface {
+			Run(string, func(*testing.T)) bool
+		}); ok {
+			runner.Run(name, func(t *testing.T) {
+				check(t)
+			})
+			continue
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			check(t)
+		}()
+		<-done
+	}
+}
+
+// violations collects the broken rules, counting the repeated ones.
+type violations struct {
+	messages []string
+	counts   map[string]int
+}
+
+func (v *violations) add(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if v.counts == nil {
+		v.counts = map[string]int{}
+	}
+
+	if v.counts[message] == 0 {
+		v.messages = append(v.messages, message)
+	}
+	v.counts[message]++
+}
+
+// report fails the test with each of the broken rules.
+func (v *violations) report(t testing.TB) {
+	for _, message := range v.messages {
+		if count := v.counts[message]; count > 1 {
+			t.Errorf("%v (%v times)", message, count)
+		} else {
+			t.Error(message)
+		}
+	}
+}
+
+// await waits on the notify channel until the condition holds or the timeout
+// passes.
+func await(notify <-chan struct{}, timeout time.Duration, condition func() bool) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for !condition() {
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return condition()
+		}
+	}
+
+	return true
+}
+
+// VerifyPublisher checks the publishers created by the factory against the
+// rules of the Reactive Streams specification covering the demand, the
+// cancellation, the serial signalling and the terminal states. Each rule is
+// reported as a subtest of t, if it is a *testing.T.
+//
+// The factory must return a publisher emitting exactly the requested number of
+// items and completing afterwards. No more than 20 items are requested from
+// it. It can return nil if it is not able to create such publisher, in which
+// case the rules depending on it are skipped.
+func VerifyPublisher(t testing.TB, factory func(elements int64) cesium.Publisher) {
+	subscribe := func(t testing.TB, elements int64) *probeSubscriber {
+		publisher := factory(elements)
+		if publisher == nil {
+			t.Skipf("The factory did not create a publisher of %v elements.", elements)
+		}
+
+		probe := newProbeSubscriber()
+		publisher.Subscribe(probe)
+		if !probe.awaitSubscription() {
+			t.Fatalf("Rule 1.9: OnSubscribe was not called within %v.", specTimeout)
+		}
+
+		return probe
+	}
+
+	verifyRules(t, []rule{
+		{
+			id:          "1.1",
+			description: "must not signal more OnNext than requested",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 5)
+
+				if probe.settled(0) {
+					for i := 1; i <= 5; i++ {
+						probe.request(1)
+						if !probe.awaitNext(i) {
+							t.Errorf("Rule 1.1: expected %v items after requesting %v, got %v.", i, i, probe.nextCount())
+							break
+						}
+
+						if !probe.settled(i) {
+							break
+						}
+					}
+				}
+
+				probe.cancel()
+				probe.report(t)
+			},
+		},
+		{
+			id:          "1.2",
+			description: "may signal fewer OnNext than requested and terminate",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 3)
+
+				probe.request(10)
+				if !probe.awaitTerminal() {
+					t.Errorf("Rule 1.2: expected the publisher to terminate after %v of 10 requested items, got %v items.", 3, probe.nextCount())
+				} else if count := probe.nextCount(); count != 3 {
+					t.Errorf("Rule 1.2: expected %v items before the terminal signal, got %v.", 3, count)
+				}
+
+				probe.report(t)
+			},
+		},
+		{
+			id:          "1.3",
+			description: "must signal serially",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 20)
+
+				wg := sync.WaitGroup{}
+				for i := 0; i < 4; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for j := 0; j < 5; j++ {
+							probe.request(1)
+						}
+					}()
+				}
+				wg.Wait()
+
+				if !probe.awaitNext(20) {
+					t.Errorf("Rule 1.3: expected %v items requested concurrently, got %v.", 20, probe.nextCount())
+				}
+
+				probe.cancel()
+				probe.report(t)
+			},
+		},
+		{
+			id:          "1.5",
+			description: "must signal OnComplete when the finite sequence ends",
+			check: func(t testing.TB) {
+				for _, elements := range []int64{0, 3} {
+					probe := subscribe(t, elements)
+
+					probe.request(elements + 1)
+					if !probe.awaitTerminal() {
+						t.Errorf("Rule 1.5: expected OnComplete after %v items, got %v items and no terminal signal.", elements, probe.nextCount())
+					} else if emission := probe.terminal(); emission.EventType != "complete" {
+						t.Errorf("Rule 1.5: expected OnComplete after %v items, got %v.", elements, emission.EventType)
+					}
+
+					probe.report(t)
+				}
+			},
+		},
+		{
+			id:          "1.7",
+			description: "must not signal after a terminal signal",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 1)
+
+				probe.request(10)
+				if !probe.awaitTerminal() {
+					t.Errorf("Rule 1.7: expected a terminal signal after %v items, got %v items.", 1, probe.nextCount())
+				}
+
+				probe.request(10)
+				probe.cancel()
+				time.Sleep(specSettle)
+
+				probe.report(t)
+			},
+		},
+		{
+			id:          "1.9",
+			description: "must call OnSubscribe once before any other signal",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 3)
+
+				probe.request(3)
+				probe.awaitNext(3)
+				probe.cancel()
+
+				probe.report(t)
+			},
+		},
+		{
+			id:          "2.13",
+			description: "must not signal nil items",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 10)
+
+				probe.request(10)
+				if !probe.awaitNext(10) {
+					t.Errorf("Rule 2.13: expected %v items, got %v.", 10, probe.nextCount())
+				}
+
+				probe.cancel()
+				probe.report(t)
+			},
+		},
+		{
+			id:          "3.3",
+			description: "must bound the recursion between Request and OnNext",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 10)
+				probe.requestOnNext()
+
+				probe.request(1)
+				if !probe.awaitNext(10) {
+					t.Errorf("Rule 3.3: expected %v items requested one by one from OnNext, got %v.", 10, probe.nextCount())
+				}
+
+				if depth := probe.recursionDepth(); depth > 1 {
+					t.Errorf("Rule 3.3: OnNext was called recursively from Request, reaching the depth of %v.", depth)
+				}
+
+				probe.cancel()
+				probe.report(t)
+			},
+		},
+		{
+			id:          "3.6",
+			description: "must ignore requests after cancellation",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 10)
+
+				probe.request(1)
+				if !probe.awaitNext(1) {
+					t.Errorf("Rule 3.6: expected %v items, got %v.", 1, probe.nextCount())
+				}
+
+				probe.cancel()
+				probe.request(5)
+				time.Sleep(specSettle)
+
+				if count := probe.nextCount(); count > 1 {
+					t.Errorf("Rule 3.6: expected no items after the cancellation, got %v.", count-1)
+				}
+
+				probe.report(t)
+			},
+		},
+		{
+			id:          "3.7",
+			description: "must ignore cancellation after cancellation",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 10)
+
+				probe.cancel()
+				probe.cancel()
+				probe.request(1)
+				time.Sleep(specSettle)
+
+				if count := probe.nextCount(); count > 0 {
+					t.Errorf("Rule 3.7: expected no items after the cancellation, got %v.", count)
+				}
+
+				probe.report(t)
+			},
+		},
+		{
+			id:          "3.9",
+			description: "must signal OnError for non-positive requests",
+			check: func(t testing.TB) {
+				for _, n := range []int64{0, -1} {
+					probe := subscribe(t, 10)
+
+					probe.subscription().Request(n)
+					if !probe.awaitTerminal() {
+						t.Errorf("Rule 3.9: expected OnError after Request(%v), got no terminal signal.", n)
+					} else if emission := probe.terminal(); emission.EventType != "error" {
+						t.Errorf("Rule 3.9: expected OnError after Request(%v), got %v.", n, emission.EventType)
+					}
+
+					if count := probe.nextCount(); count > 0 {
+						t.Errorf("Rule 3.9: expected no items after Request(%v), got %v.", n, count)
+					}
+
+					probe.report(t)
+				}
+			},
+		},
+		{
+			id:          "3.17",
+			description: "must support the demand of up to math.MaxInt64",
+			check: func(t testing.TB) {
+				probe := subscribe(t, 3)
+
+				probe.request(math.MaxInt64)
+				probe.request(math.MaxInt64)
+				if !probe.awaitTerminal() {
+					t.Errorf("Rule 3.17: expected a terminal signal after %v items, got %v items.", 3, probe.nextCount())
+				} else if emission := probe.terminal(); emission.EventType != "complete" {
+					t.Errorf("Rule 3.17: expected OnComplete after requesting math.MaxInt64 twice, got %v %v.", emission.EventType, emission.Err)
+				}
+
+				probe.report(t)
+			},
+		},
+	})
+}
+
+// probeSubscriber records the signals of the verified publisher along with the
+// rules they break.
+type probeSubscriber struct {
+	mux               sync.Mutex
+	notify            chan struct{}
+	sub               cesium.Subscription
+	subscribed        int
+	emissions         []MaterializedEmission
+	demand            int64
+	terminated        bool
+	violations        violations
+	signalling        int32
+	depth             int
+	maxDepth          int
+	requestFromOnNext bool
+}
+
+func newProbeSubscriber() *probeSubscriber {
+	return &probeSubscriber{notify: make(chan struct{}, 1)}
+}
+
+func (p *probeSubscriber) violate(format string, args ...interface{}) {
+	p.violations.add(format, args...)
+}
+
+func (p *probeSubscriber) signal() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// enter marks the start of a signal, noting if another one is in progress.
+func (p *probeSubscriber) enter(name string) {
+	if !atomic.CompareAndSwapInt32(&p.signalling, 0, 1) {
+		p.mux.Lock()
+		p.violate("Rule 1.3: %v was signalled concurrently with another signal.", name)
+		p.mux.Unlock()
+	}
+}
+
+func (p *probeSubscriber) leave() {
+	atomic.StoreInt32(&p.signalling, 0)
+}
+
+// record checks the signal against the rules and stores it.
+func (p *probeSubscriber) record(emission MaterializedEmission) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	defer p.signal()
+
+	if p.subscribed == 0 {
+		p.violate("Rule 1.9: %v was signalled before OnSubscribe.", emission.EventType)
+	}
+
+	if p.terminated {
+		p.violate("Rule 1.7: %v was signalled after a terminal signal.", emission.EventType)
+	}
+
+	switch emission.EventType {
+	case "next":
+		if emission.Value == nil {
+			p.violate("Rule 2.13: OnNext was signalled with a nil item.")
+		}
+
+		if p.demand == 0 {
+			p.violate("Rule 1.1: OnNext was signalled without demand.")
+		} else if p.demand != math.MaxInt64 {
+			p.demand--
+		}
+	default:
+		p.terminated = true
+	}
+
+	p.emissions = append(p.emissions, emission)
+}
+
+func (p *probeSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	p.enter("OnSubscribe")
+	defer p.leave()
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	defer p.signal()
+
+	if subscription == nil {
+		p.violate("Rule 1.9: OnSubscribe was signalled with a nil subscription.")
+		return
+	}
+
+	p.subscribed++
+	if p.subscribed > 1 {
+		p.violate("Rule 1.9: OnSubscribe was signalled %v times.", p.subscribed)
+		return
+	}
+
+	if len(p.emissions) > 0 {
+		p.violate("Rule 1.9: OnSubscribe was signalled after %v.", p.emissions[0].EventType)
+	}
+
+	p.sub = subscription
+}
+
+func (p *probeSubscriber) OnNext(t cesium.T) {
+	p.enter("OnNext")
+	p.record(MaterializedEmission{EventType: "next", Value: t})
+
+	p.mux.Lock()
+	requestFromOnNext := p.requestFromOnNext
+	p.depth++
+	if p.depth > p.maxDepth {
+		p.maxDepth = p.depth
+	}
+	p.mux.Unlock()
+
+	// The signal is over once recorded, the request below may legitimately
+	// lead to the next one.
+	p.leave()
+
+	if requestFromOnNext {
+		p.request(1)
+	}
+
+	p.mux.Lock()
+	p.depth--
+	p.mux.Unlock()
+}
+
+func (p *probeSubscriber) OnComplete() {
+	p.enter("OnComplete")
+	defer p.leave()
+
+	p.record(MaterializedEmission{EventType: "complete"})
+}
+
+func (p *probeSubscriber) OnError(err error) {
+	p.enter("OnError")
+	defer p.leave()
+
+	p.record(MaterializedEmission{EventType: "error", Err: err})
+}
+
+func (p *probeSubscriber) subscription() cesium.Subscription {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.sub
+}
+
+func (p *probeSubscriber) awaitSubscription() bool {
+	return await(p.notify, specTimeout, func() bool {
+		return p.subscription() != nil
+	})
+}
+
+// request adds to the demand before requesting, as the items can be
+// signalled before the request returns.
+func (p *probeSubscriber) request(n int64) {
+	p.mux.Lock()
+	if p.demand > math.MaxInt64-n {
+		p.demand = math.MaxInt64
+	} else {
+		p.demand += n
+	}
+	p.mux.Unlock()
+
+	p.subscription().Request(n)
+}
+
+func (p *probeSubscriber) cancel() {
+	p.subscription().Cancel()
+}
+
+func (p *probeSubscriber) nextCount() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	count := 0
+	for _, e := range p.emissions {
+		if e.EventType == "next" {
+			count++
+		}
+	}
+
+	return count
+}
+
+// terminal returns the terminal signal, if there is one.
+func (p *probeSubscriber) terminal() *MaterializedEmission {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	for i, e := range p.emissions {
+		if e.EventType != "next" {
+			return &p.emissions[i]
+		}
+	}
+
+	return nil
+}
+
+func (p *probeSubscriber) awaitNext(count int) bool {
+	return await(p.notify, specTimeout, func() bool {
+		return p.nextCount() >= count
+	})
+}
+
+func (p *probeSubscriber) awaitTerminal() bool {
+	return await(p.notify, specTimeout, func() bool {
+		return p.terminal() != nil
+	})
+}
+
+// settled reports whether the number of items stays at count for a while.
+func (p *probeSubscriber) settled(count int) bool {
+	time.Sleep(specSettle)
+	return p.nextCount() == count
+}
+
+// requestOnNext makes the probe request the next item from OnNext.
+func (p *probeSubscriber) requestOnNext() {
+	p.mux.Lock()
+	p.requestFromOnNext = true
+	p.mux.Unlock()
+}
+
+func (p *probeSubscriber) recursionDepth() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.maxDepth
+}
+
+// report fails the test with the rules broken by the publisher.
+func (p *probeSubscriber) report(t testing.TB) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.violations.report(t)
+}
+
+// VerifySubscriber checks the subscribers created by the factory against the
+// rules of the Reactive Streams specification covering the demand, the
+// cancellation, the serial signalling and the terminal states. Each rule is
+// reported as a subtest of t, if it is a *testing.T.
+//
+// The subscribers receive the items created by the element function, no more
+// than 10 of them. They are expected to request the items on their own after
+// OnSubscribe.
+func VerifySubscriber(t testing.TB, factory func() cesium.Subscriber, element func(i int64) cesium.T) {
+	// call calls the subscriber, reporting the panics as the rule 2.13 requires
+	// the signal methods to return normally.
+	call := func(t testing.TB, name string, f func()) (ok bool) {
+		defer func() {
+			if p := recover(); p != nil {
+				t.Errorf("Rule 2.13: %v panicked: %v\n%s", name, p, debug.Stack())
+				ok = false
+			}
+		}()
+
+		f()
+		return true
+	}
+
+	// feed delivers the items to the subscriber as it requests them, completing
+	// once there are no more items or the subscriber stops requesting them.
+	feed := func(t testing.TB, s cesium.Subscriber, probe *probeSubscription, elements int64) {
+		if !call(t, "OnSubscribe", func() { s.OnSubscribe(probe) }) {
+			return
+		}
+
+		for i := int64(0); i < elements; i++ {
+			if !probe.awaitDemand() {
+				if i == 0 {
+					t.Errorf("Rule 2.1: no items were requested within %v after OnSubscribe.", specTimeout)
+				}
+				break
+			}
+
+			if probe.isCancelled() {
+				return
+			}
+
+			probe.take()
+			if !call(t, "OnNext", func() { s.OnNext(element(i)) }) {
+				return
+			}
+		}
+
+		probe.terminating()
+		call(t, "OnComplete", func() { s.OnComplete() })
+	}
+
+	verifyRules(t, []rule{
+		{
+			id:          "2.1",
+			description: "must signal demand through Request",
+			check: func(t testing.TB) {
+				probe := newProbeSubscription()
+				feed(t, factory(), probe, 10)
+				probe.report(t)
+			},
+		},
+		{
+			id:          "2.3",
+			description: "must not call the Subscription from OnComplete or OnError",
+			check: func(t testing.TB) {
+				probe := newProbeSubscription()
+				s := factory()
+				if call(t, "OnSubscribe", func() { s.OnSubscribe(probe) }) {
+					probe.awaitDemand()
+					probe.terminating()
+					call(t, "OnComplete", func() { s.OnComplete() })
+				}
+				probe.report(t)
+
+				probe = newProbeSubscription()
+				s = factory()
+				if call(t, "OnSubscribe", func() { s.OnSubscribe(probe) }) {
+					probe.awaitDemand()
+					probe.terminating()
+					call(t, "OnError", func() { s.OnError(fmt.Errorf("verifier error")) })
+				}
+				probe.report(t)
+			},
+		},
+		{
+			id:          "2.5",
+			description: "must cancel a second Subscription",
+			check: func(t testing.TB) {
+				first, second := newProbeSubscription(), newProbeSubscription()
+				s := factory()
+
+				if call(t, "OnSubscribe", func() { s.OnSubscribe(first) }) &&
+					call(t, "OnSubscribe", func() { s.OnSubscribe(second) }) {
+					if !await(second.notify, specTimeout, second.isCancelled) {
+						t.Errorf("Rule 2.5: the second Subscription was not cancelled within %v.", specTimeout)
+					}
+
+					if first.isCancelled() {
+						t.Errorf("Rule 2.5: the first Subscription was cancelled.")
+					}
+				}
+
+				first.report(t)
+				second.report(t)
+			},
+		},
+		{
+			id:          "2.7",
+			description: "must call the Subscription serially",
+			check: func(t testing.TB) {
+				probe := newProbeSubscription()
+				feed(t, factory(), probe, 10)
+				probe.report(t)
+			},
+		},
+		{
+			id:          "2.9",
+			description: "must accept OnComplete without a preceding Request",
+			check: func(t testing.TB) {
+				probe := newProbeSubscription()
+				s := factory()
+				if call(t, "OnSubscribe", func() { s.OnSubscribe(probe) }) {
+					probe.terminating()
+					call(t, "OnComplete", func() { s.OnComplete() })
+				}
+				probe.report(t)
+			},
+		},
+		{
+			id:          "2.10",
+			description: "must accept OnError without a preceding Request",
+			check: func(t testing.TB) {
+				probe := newProbeSubscription()
+				s := factory()
+				if call(t, "OnSubscribe", func() { s.OnSubscribe(probe) }) {
+					probe.terminating()
+					call(t, "OnError", func() { s.OnError(fmt.Errorf("verifier error")) })
+				}
+				probe.report(t)
+			},
+		},
+	})
+}
+
+// probeSubscription records the calls of the verified subscriber along with
+// the rules they break.
+type probeSubscription struct {
+	mux        sync.Mutex
+	notify     chan struct{}
+	demand     int64
+	cancelled  bool
+	terminated bool
+	calling    int32
+	violations violations
+}
+
+func newProbeSubscription() *probeSubscription {
+	return &probeSubscription{notify: make(chan struct{}, 1)}
+}
+
+// enter marks the start of a call, noting if another one is in progress or if
+// it is made after a terminal signal.
+func (p *probeSubscription) enter(name string) {
+	concurrent := !atomic.CompareAndSwapInt32(&p.calling, 0, 1)
+
+	p.mux.Lock()
+	if concurrent {
+		p.violations.add("Rule 2.7: %v was called concurrently with another call.", name)
+	}
+	if p.terminated {
+		p.violations.add("Rule 2.3: %v was called after OnComplete or OnError.", name)
+	}
+	p.mux.Unlock()
+}
+
+func (p *probeSubscription) leave() {
+	atomic.StoreInt32(&p.calling, 0)
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (p *probeSubscription) Request(n int64) {
+	p.enter("Request")
+	defer p.leave()
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if n <= 0 {
+		p.violations.add("Rule 3.9: Request was called with %v.", n)
+		return
+	}
+
+	if p.demand > math.MaxInt64-n {
+		p.demand = math.MaxInt64
+	} else {
+		p.demand += n
+	}
+}
+
+func (p *probeSubscription) RequestUnbounded() {
+	p.Request(math.MaxInt64)
+}
+
+func (p *probeSubscription) Cancel() {
+	p.enter("Cancel")
+	defer p.leave()
+
+	p.mux.Lock()
+	p.cancelled = true
+	p.mux.Unlock()
+}
+
+func (p *probeSubscription) isCancelled() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.cancelled
+}
+
+func (p *probeSubscription) hasDemand() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.demand > 0 || p.cancelled
+}
+
+func (p *probeSubscription) awaitDemand() bool {
+	return await(p.notify, specTimeout, p.hasDemand)
+}
+
+// take consumes a unit of demand for an item about to be signalled.
+func (p *probeSubscription) take() {
+	p.mux.Lock()
+	if p.demand != math.MaxInt64 {
+		p.demand--
+	}
+	p.mux.Unlock()
+}
+
+// terminating marks that the subscriber is about to receive a terminal signal,
+// so it must not call the subscription anymore.
+func (p *probeSubscription) terminating() {
+	p.mux.Lock()
+	p.terminated = true
+	p.mux.Unlock()
+}
+
+// report fails the test with the rules broken by the subscriber.
+func (p *probeSubscription) report(t testing.TB) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.violations.report(t)
+}
@@ -0,0 +1,270 @@
+package verifier_test
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DusanKasan/cesium"
+	"github.com/DusanKasan/cesium/flux"
+	"github.com/DusanKasan/cesium/mono"
+	"github.com/DusanKasan/cesium/verifier"
+)
+
+func TestVerifyPublisherRange(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		return flux.Range(0, int(elements))
+	})
+}
+
+func TestVerifyPublisherFromSlice(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		items := make([]cesium.T, elements)
+		for i := range items {
+			items[i] = i
+		}
+
+		return flux.FromSlice(items)
+	})
+}
+
+func TestVerifyPublisherGenerate(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		i := int64(0)
+
+		return flux.Generate(func(s cesium.SynchronousSink) {
+			if i == elements {
+				s.Complete()
+				return
+			}
+
+			s.Next(i)
+			i++
+		})
+	})
+}
+
+func TestVerifyPublisherFilteredRange(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		return flux.Range(0, int(elements*2)).
+			Map(func(t cesium.T) cesium.T {
+				return t
+			}).
+			Filter(func(t cesium.T) bool {
+				return t.(int64)%2 == 0
+			})
+	})
+}
+
+func TestVerifyPublisherMono(t *testing.T) {
+	verifier.VerifyPublisher(t, func(elements int64) cesium.Publisher {
+		switch elements {
+		case 0:
+			return mono.Empty()
+		case 1:
+			return mono.Just(1)
+		default:
+			return nil
+		}
+	})
+}
+
+// oneByOneSubscriber requests the items one at a time.
+type oneByOneSubscriber struct {
+	subscription cesium.Subscription
+	items        []cesium.T
+}
+
+func (s *oneByOneSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	if s.subscription != nil {
+		subscription.Cancel()
+		return
+	}
+
+	s.subscription = subscription
+	s.subscription.Request(1)
+}
+
+func (s *oneByOneSubscriber) OnNext(t cesium.T) {
+	s.items = append(s.items, t)
+	s.subscription.Request(1)
+}
+
+func (s *oneByOneSubscriber) OnError(error) {}
+func (s *oneByOneSubscriber) OnComplete()   {}
+
+func TestVerifySubscriber(t *testing.T) {
+	verifier.VerifySubscriber(
+		t,
+		func() cesium.Subscriber {
+			return &oneByOneSubscriber{}
+		},
+		func(i int64) cesium.T {
+			return i
+		},
+	)
+}
+
+// recordingTB records the failures reported by the verifier instead of
+// failing the test. FailNow ends the calling goroutine, like in testing.T.
+type recordingTB struct {
+	testing.TB
+
+	mux      sync.Mutex
+	messages []string
+}
+
+func (r *recordingTB) record(message string) {
+	r.mux.Lock()
+	r.messages = append(r.messages, message)
+	r.mux.Unlock()
+}
+
+func (r *recordingTB) Error(args ...interface{}) {
+	r.record(fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.record(fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.record(fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+func (r *recordingTB) Skipf(format string, args ...interface{}) {
+	runtime.Goexit()
+}
+
+// expectReported checks that the rule was reported as broken.
+func (r *recordingTB) expectReported(t *testing.T, rule string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, message := range r.messages {
+		if strings.HasPrefix(message, "Rule "+rule+":") {
+			return
+		}
+	}
+
+	t.Errorf("Expected the rule %v to be reported, got %q", rule, r.messages)
+}
+
+// brokenPublisher breaks the rules by calling the subscriber directly.
+type brokenPublisher func(cesium.Subscriber, cesium.Subscription)
+
+func (p brokenPublisher) Subscribe(subscriber cesium.Subscriber) cesium.Subscription {
+	s := &brokenSubscription{}
+	p(subscriber, s)
+	return s
+}
+
+// brokenSubscription calls onRequest with each request, if it is set before
+// the subscription is passed to the subscriber.
+type brokenSubscription struct {
+	onRequest func(int64)
+}
+
+func (s *brokenSubscription) Request(n int64) {
+	if s.onRequest != nil {
+		s.onRequest(n)
+	}
+}
+
+func (s *brokenSubscription) RequestUnbounded() {
+	s.Request(math.MaxInt64)
+}
+
+func (s *brokenSubscription) Cancel() {}
+
+// requested returns a brokenPublisher subscribing the subscriber and calling
+// the function upon the first request.
+func requested(f func(subscriber cesium.Subscriber)) brokenPublisher {
+	return func(subscriber cesium.Subscriber, s cesium.Subscription) {
+		called := int32(0)
+		s.(*brokenSubscription).onRequest = func(int64) {
+			if atomic.CompareAndSwapInt32(&called, 0, 1) {
+				f(subscriber)
+			}
+		}
+		subscriber.OnSubscribe(s)
+	}
+}
+
+func TestVerifyPublisherReportsItemWithoutDemand(t *testing.T) {
+	r := &recordingTB{TB: t}
+	verifier.VerifyPublisher(r, func(elements int64) cesium.Publisher {
+		return brokenPublisher(func(subscriber cesium.Subscriber, s cesium.Subscription) {
+			subscriber.OnSubscribe(s)
+			subscriber.OnNext(1)
+		})
+	})
+
+	r.expectReported(t, "1.1")
+}
+
+func TestVerifyPublisherReportsNilItem(t *testing.T) {
+	r := &recordingTB{TB: t}
+	verifier.VerifyPublisher(r, func(elements int64) cesium.Publisher {
+		return requested(func(subscriber cesium.Subscriber) {
+			subscriber.OnNext(nil)
+		})
+	})
+
+	r.expectReported(t, "2.13")
+}
+
+func TestVerifyPublisherReportsSignalAfterTerminal(t *testing.T) {
+	r := &recordingTB{TB: t}
+	verifier.VerifyPublisher(r, func(elements int64) cesium.Publisher {
+		return requested(func(subscriber cesium.Subscriber) {
+			subscriber.OnComplete()
+			subscriber.OnNext(1)
+		})
+	})
+
+	r.expectReported(t, "1.7")
+}
+
+func TestVerifyPublisherReportsMissingOnSubscribe(t *testing.T) {
+	r := &recordingTB{TB: t}
+	verifier.VerifyPublisher(r, func(elements int64) cesium.Publisher {
+		return brokenPublisher(func(cesium.Subscriber, cesium.Subscription) {})
+	})
+
+	r.expectReported(t, "1.9")
+}
+
+// panickingSubscriber requests the items and panics upon receiving them.
+type panickingSubscriber struct{}
+
+func (s *panickingSubscriber) OnSubscribe(subscription cesium.Subscription) {
+	subscription.Request(1)
+}
+
+func (s *panickingSubscriber) OnNext(cesium.T) {
+	panic("boom")
+}
+
+func (s *panickingSubscriber) OnError(error) {}
+func (s *panickingSubscriber) OnComplete()   {}
+
+func TestVerifySubscriberReportsPanic(t *testing.T) {
+	r := &recordingTB{TB: t}
+	verifier.VerifySubscriber(
+		r,
+		func() cesium.Subscriber {
+			return &panickingSubscriber{}
+		},
+		func(i int64) cesium.T {
+			return i
+		},
+	)
+
+	r.expectReported(t, "2.13")
+}
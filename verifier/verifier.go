@@ -190,6 +190,17 @@ func (sv *StepVerifier) ExpectError(err error) *StepVerifier {
 	return sv
 }
 
+// ExpectErrorMatches adds an expectation that expects an error signal for
+// which the callback returns true.
+func (sv *StepVerifier) ExpectErrorMatches(f func(error) bool) *StepVerifier {
+	sv.expectations = append(sv.expectations, expectation{
+		expectationType: "errorMatches",
+		value:           f,
+	})
+
+	return sv
+}
+
 // ThenCancel cancels the underlying subscription.
 func (sv *StepVerifier) ThenCancel() *StepVerifier {
 	sv.expectations = append(sv.expectations, expectation{
@@ -409,6 +420,16 @@ func compareExpectationAndEmission(expected expectation, actual MaterializedEmis
 			t.Errorf("Invalid error! Expected: %v, Got: %v", expected.err, actual.Err)
 			return false
 		}
+	case "errorMatches":
+		if actual.EventType != "error" {
+			t.Errorf("Invalid emission type! Expected: %v, Got: %v", "error", actual.EventType)
+			return false
+		}
+
+		if !expected.value.(func(error) bool)(actual.Err) {
+			t.Errorf("ExpectErrorMatches returned false for %v", actual.Err)
+			return false
+		}
 	}
 
 	return true